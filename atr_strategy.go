@@ -0,0 +1,58 @@
+package main
+
+// ATRStrategy is an ATR-pin inspired breakout strategy. It enters long when a
+// candle's high-low range expands well beyond the recent Average True Range
+// while price has pulled back from the prior close, then protects the
+// position with a trailing stop at close-k*ATR that only ever ratchets up.
+type ATRStrategy struct {
+	Period           int     // ATR lookback, Wilder-smoothed (e.g. 14)
+	RangeMultiplier  float64 // candle range must exceed multiplier*ATR to trigger
+	MinPriceRangePct float64 // close must sit at least this % below the prior close
+	TrailingATRMult  float64 // trailing stop = close - TrailingATRMult*ATR
+
+	trailingStops map[string]float64
+}
+
+func NewATRStrategy(period int, rangeMultiplier, minPriceRangePct, trailingATRMult float64) *ATRStrategy {
+	return &ATRStrategy{
+		Period:           period,
+		RangeMultiplier:  rangeMultiplier,
+		MinPriceRangePct: minPriceRangePct,
+		TrailingATRMult:  trailingATRMult,
+		trailingStops:    make(map[string]float64),
+	}
+}
+
+func (s *ATRStrategy) OnKline(symbol string, klines []Kline) Signal {
+	if len(klines) < s.Period+2 {
+		return Signal{Action: Hold}
+	}
+	atr := calcATR(klines, s.Period)
+	if atr <= 0 {
+		return Signal{Action: Hold}
+	}
+	last := klines[len(klines)-1]
+	prevClose := klines[len(klines)-2].Close
+	candleRange := last.High - last.Low
+
+	stop := last.Close - s.TrailingATRMult*atr
+	if prev, ok := s.trailingStops[symbol]; ok && prev > stop {
+		stop = prev
+	}
+	s.trailingStops[symbol] = stop
+
+	if candleRange > s.RangeMultiplier*atr && last.Close <= prevClose*(1-s.MinPriceRangePct/100) {
+		return Signal{Action: Buy, StopPrice: stop}
+	}
+	if last.Close <= stop {
+		return Signal{Action: Sell, StopPrice: stop}
+	}
+	return Signal{Action: Hold, StopPrice: stop}
+}
+
+// OnPositionClosed clears symbol's stored trailing stop once its position
+// exits, so a later re-entry starts from close-k*ATR again instead of
+// inheriting the prior position's (possibly much higher) ratcheted stop.
+func (s *ATRStrategy) OnPositionClosed(symbol string) {
+	delete(s.trailingStops, symbol)
+}
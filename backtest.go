@@ -0,0 +1,415 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	"go_auto_trade/exchange"
+)
+
+// BacktestConfig describes a historical replay run: which symbols to trade,
+// over what date range and candle interval, the starting balances per asset,
+// and the fee/slippage model applied to simulated fills. Every simulated fill
+// is a market order, so it always pays TakerFeePct; there's no maker fee to
+// model since the replay never simulates resting limit orders.
+type BacktestConfig struct {
+	Symbols          []string           `json:"symbols"`
+	SymbolSpecs      []SymbolSpec       `json:"symbol_specs,omitempty"` // optional Base/Quote/tick-size overrides; falls back to splitting the trailing "USDT" off Symbols entries not listed here
+	Interval         string             `json:"interval"`
+	StartTimeMs      int64              `json:"start_time_ms"`
+	EndTimeMs        int64              `json:"end_time_ms"`
+	StartingBalances map[string]float64 `json:"starting_balances"`
+	TakerFeePct      float64            `json:"taker_fee_pct"`
+	SlippagePct      float64            `json:"slippage_pct"`
+	EquityCurveCSV   string             `json:"equity_curve_csv"`
+}
+
+// buildBacktestSymbolTable resolves each configured symbol to its
+// exchange.Symbol (base/quote + tick sizes) via cfg.SymbolSpecs, the same
+// routing table LiveExchange uses for live trading. Symbols without a spec
+// fall back to splitting the trailing "USDT" off, preserving the behavior of
+// configs written before SymbolSpecs existed.
+func buildBacktestSymbolTable(cfg BacktestConfig) map[string]exchange.Symbol {
+	specs := make(map[string]exchange.Symbol, len(cfg.SymbolSpecs))
+	for _, s := range cfg.SymbolSpecs {
+		specs[s.Symbol] = exchange.Symbol{
+			Base: s.Base, Quote: s.Quote,
+			AmountTickSize: s.AmountTickSize, PriceTickSize: s.PriceTickSize,
+		}
+	}
+	table := make(map[string]exchange.Symbol, len(cfg.Symbols))
+	for _, sym := range cfg.Symbols {
+		if spec, ok := specs[sym]; ok {
+			table[sym] = spec
+			continue
+		}
+		table[sym] = exchange.Symbol{Base: sym[:len(sym)-4], Quote: "USDT"}
+	}
+	return table
+}
+
+func loadBacktestConfig(path string) (BacktestConfig, error) {
+	var cfg BacktestConfig
+	f, err := os.Open(path)
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+type backtestCandle struct {
+	OpenTimeMs int64
+	Open       float64
+	High       float64
+	Low        float64
+	Close      float64
+}
+
+type backtestTrade struct {
+	Symbol string
+	Side   string
+	Price  float64
+	Qty    float64
+	Fee    float64
+	TimeMs int64
+	PnL    float64
+}
+
+// BacktestExchange implements Exchange by replaying preloaded historical
+// candles and simulating market-order fills with a taker fee and slippage
+// model, modeled on the bbgo backtest account config.
+type BacktestExchange struct {
+	cfg     BacktestConfig
+	symbols map[string]exchange.Symbol
+	candles map[string][]backtestCandle
+	cursor  map[string]int
+
+	balances   map[string]float64
+	entryPrice map[string]float64
+	entryQty   map[string]float64
+	trades     []backtestTrade
+}
+
+func NewBacktestExchange(cfg BacktestConfig) (*BacktestExchange, error) {
+	be := &BacktestExchange{
+		cfg:        cfg,
+		symbols:    buildBacktestSymbolTable(cfg),
+		candles:    make(map[string][]backtestCandle),
+		cursor:     make(map[string]int),
+		balances:   make(map[string]float64),
+		entryPrice: make(map[string]float64),
+		entryQty:   make(map[string]float64),
+	}
+	for asset, bal := range cfg.StartingBalances {
+		be.balances[asset] = bal
+	}
+	history := exchange.NewBinance("", "")
+	for _, sym := range cfg.Symbols {
+		candles, err := fetchHistoricalKlines(history, be.symbols[sym], cfg.Interval, cfg.StartTimeMs, cfg.EndTimeMs)
+		if err != nil {
+			return nil, fmt.Errorf("fetch klines for %s: %w", sym, err)
+		}
+		if len(candles) == 0 {
+			return nil, fmt.Errorf("no historical klines returned for %s", sym)
+		}
+		be.candles[sym] = candles
+	}
+	return be, nil
+}
+
+// fetchHistoricalKlines loads a backtest's historical window from Binance's
+// public klines endpoint via exchange.Binance.GetKlinesRange, then converts
+// the result to backtestCandle. Historical data is intentionally sourced
+// from Binance regardless of which venue a symbol is configured for live,
+// since Binance has the deepest and longest public candle history.
+func fetchHistoricalKlines(history *exchange.Binance, sym exchange.Symbol, interval string, startMs, endMs int64) ([]backtestCandle, error) {
+	klines, err := history.GetKlinesRange(sym, interval, startMs, endMs)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]backtestCandle, len(klines))
+	for i, k := range klines {
+		out[i] = backtestCandle{OpenTimeMs: k.OpenTimeMs, Open: k.Open, High: k.High, Low: k.Low, Close: k.Close}
+	}
+	return out, nil
+}
+
+func (b *BacktestExchange) minLen() int {
+	min := -1
+	for _, c := range b.candles {
+		if min == -1 || len(c) < min {
+			min = len(c)
+		}
+	}
+	if min == -1 {
+		return 0
+	}
+	return min
+}
+
+// advance moves every symbol's cursor to candle index i.
+func (b *BacktestExchange) advance(i int) {
+	for sym := range b.candles {
+		b.cursor[sym] = i
+	}
+}
+
+func (b *BacktestExchange) currentTimeMs(sym string) int64 {
+	i := b.cursor[sym]
+	return b.candles[sym][i].OpenTimeMs
+}
+
+func (b *BacktestExchange) GetKlines(symbol, _ string, limit int) []Kline {
+	candles := b.candles[symbol]
+	i := b.cursor[symbol]
+	start := i - limit + 1
+	if start < 0 {
+		start = 0
+	}
+	var klines []Kline
+	for _, c := range candles[start : i+1] {
+		klines = append(klines, Kline{OpenTimeMs: c.OpenTimeMs, Open: c.Open, High: c.High, Low: c.Low, Close: c.Close})
+	}
+	return klines
+}
+
+func (b *BacktestExchange) GetCurrentPrice(symbol string) float64 {
+	i := b.cursor[symbol]
+	return b.candles[symbol][i].Close
+}
+
+func (b *BacktestExchange) GetBalance(_, asset string) float64 {
+	return b.balances[asset]
+}
+
+// Get24hVolume has no historical volume feed to replay against, so the
+// liquidity filter is treated as always-passing during a backtest.
+func (b *BacktestExchange) Get24hVolume(_ string) float64 {
+	return math.MaxFloat64
+}
+
+func (b *BacktestExchange) BaseAsset(symbol string) string {
+	return b.symbols[symbol].Base
+}
+
+func (b *BacktestExchange) PlaceMarketOrder(symbol, side string, quantity float64) (float64, float64) {
+	if quantity <= 0 {
+		return 0, 0
+	}
+	price := b.GetCurrentPrice(symbol)
+	coinSym := b.BaseAsset(symbol)
+	fillPrice := price
+	if side == "BUY" {
+		fillPrice = price * (1 + b.cfg.SlippagePct)
+	} else {
+		fillPrice = price * (1 - b.cfg.SlippagePct)
+	}
+	notional := fillPrice * quantity
+	fee := notional * b.cfg.TakerFeePct
+
+	var pnl float64
+	switch side {
+	case "BUY":
+		cost := notional + fee
+		if cost > b.balances["USDT"] {
+			return 0, 0
+		}
+		b.balances["USDT"] -= cost
+		b.balances[coinSym] += quantity
+		b.entryPrice[symbol] = fillPrice
+		b.entryQty[symbol] = quantity
+	case "SELL":
+		if quantity > b.balances[coinSym] {
+			quantity = b.balances[coinSym]
+		}
+		proceeds := fillPrice*quantity - fee
+		b.balances[coinSym] -= quantity
+		b.balances["USDT"] += proceeds
+		pnl = (fillPrice - b.entryPrice[symbol]) * quantity
+		b.entryPrice[symbol] = 0
+		b.entryQty[symbol] = 0
+	}
+	b.trades = append(b.trades, backtestTrade{
+		Symbol: symbol, Side: side, Price: fillPrice, Qty: quantity, Fee: fee,
+		TimeMs: b.currentTimeMs(symbol), PnL: pnl,
+	})
+	return fillPrice, quantity
+}
+
+func (b *BacktestExchange) equity() float64 {
+	eq := b.balances["USDT"]
+	for sym := range b.candles {
+		coinSym := b.BaseAsset(sym)
+		if qty, ok := b.balances[coinSym]; ok && qty > 0 {
+			eq += qty * b.GetCurrentPrice(sym)
+		}
+	}
+	return eq
+}
+
+type EquityPoint struct {
+	TimeMs int64
+	Equity float64
+}
+
+// BacktestReport summarizes the outcome of a replay run.
+type BacktestReport struct {
+	TradeCount    int
+	WinRate       float64
+	MaxDrawdown   float64
+	Sharpe        float64
+	RealizedPnL   map[string]float64
+	UnrealizedPnL map[string]float64
+	EquityCurve   []EquityPoint
+}
+
+// WriteEquityCSV dumps the equity curve as time_ms,equity rows.
+func (r *BacktestReport) WriteEquityCSV(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	defer w.Flush()
+	if err := w.Write([]string{"time_ms", "equity"}); err != nil {
+		return err
+	}
+	for _, p := range r.EquityCurve {
+		if err := w.Write([]string{strconv.FormatInt(p.TimeMs, 10), strconv.FormatFloat(p.Equity, 'f', 8, 64)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunBacktest replays historical candles through tradePortfolioOnce using a
+// BacktestExchange, then derives performance metrics from the resulting
+// trades and equity curve.
+func RunBacktest(
+	cfg BacktestConfig,
+	portfolio map[string]float64,
+	strategies map[string]Strategy,
+	buyInterval int64,
+	stopLossPct float64,
+	takeProfitPct float64,
+	candleInterval string,
+) (*BacktestReport, error) {
+	be, err := NewBacktestExchange(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[string]*PositionData)
+	lastBuyTime := make(map[string]int64)
+	for sym := range portfolio {
+		positions[sym] = &PositionData{InPosition: false, LastBuyPrice: 0, Qty: 0}
+		lastBuyTime[sym] = 0
+	}
+
+	n := be.minLen()
+	const warmup = 60
+	var equityCurve []EquityPoint
+	for i := warmup; i < n; i++ {
+		be.advance(i)
+		tNow := be.candles[cfg.Symbols[0]][i].OpenTimeMs / 1000
+		tradePortfolioOnce(be, portfolio, strategies, positions, lastBuyTime, nil, tNow,
+			buyInterval, stopLossPct, takeProfitPct, candleInterval, 0, 0)
+		equityCurve = append(equityCurve, EquityPoint{TimeMs: be.candles[cfg.Symbols[0]][i].OpenTimeMs, Equity: be.equity()})
+	}
+
+	return buildBacktestReport(be, equityCurve), nil
+}
+
+func buildBacktestReport(be *BacktestExchange, equityCurve []EquityPoint) *BacktestReport {
+	report := &BacktestReport{
+		RealizedPnL:   make(map[string]float64),
+		UnrealizedPnL: make(map[string]float64),
+		EquityCurve:   equityCurve,
+	}
+	var wins, sells int
+	for _, t := range be.trades {
+		report.TradeCount++
+		if t.Side == "SELL" {
+			sells++
+			report.RealizedPnL[t.Symbol] += t.PnL
+			if t.PnL > 0 {
+				wins++
+			}
+		}
+	}
+	if sells > 0 {
+		report.WinRate = float64(wins) / float64(sells)
+	}
+	for sym := range be.candles {
+		coinSym := be.BaseAsset(sym)
+		qty := be.balances[coinSym]
+		if qty > 0 {
+			report.UnrealizedPnL[sym] = (be.GetCurrentPrice(sym) - be.entryPrice[sym]) * qty
+		}
+	}
+	report.MaxDrawdown = maxDrawdown(equityCurve)
+	report.Sharpe = sharpeRatio(equityCurve)
+	return report
+}
+
+func maxDrawdown(curve []EquityPoint) float64 {
+	if len(curve) == 0 {
+		return 0
+	}
+	peak := curve[0].Equity
+	var maxDD float64
+	for _, p := range curve {
+		if p.Equity > peak {
+			peak = p.Equity
+		}
+		if peak > 0 {
+			dd := (peak - p.Equity) / peak
+			if dd > maxDD {
+				maxDD = dd
+			}
+		}
+	}
+	return maxDD
+}
+
+func sharpeRatio(curve []EquityPoint) float64 {
+	if len(curve) < 2 {
+		return 0
+	}
+	var returns []float64
+	for i := 1; i < len(curve); i++ {
+		prev := curve[i-1].Equity
+		if prev == 0 {
+			continue
+		}
+		returns = append(returns, (curve[i].Equity-prev)/prev)
+	}
+	if len(returns) == 0 {
+		return 0
+	}
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns))
+	stddev := math.Sqrt(variance)
+	if stddev == 0 {
+		return 0
+	}
+	return mean / stddev * math.Sqrt(float64(len(returns)))
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestMaxDrawdown(t *testing.T) {
+	curve := []EquityPoint{
+		{Equity: 100}, {Equity: 120}, {Equity: 90}, {Equity: 150}, {Equity: 105},
+	}
+	// Worst peak-to-trough drop is 150 -> 105, i.e. 45/150 = 0.3; the
+	// earlier 120 -> 90 drop (0.25) is smaller and shouldn't win.
+	const want = 0.3
+	if got := maxDrawdown(curve); got != want {
+		t.Errorf("maxDrawdown = %v, want %v", got, want)
+	}
+}
+
+func TestMaxDrawdownEmptyCurve(t *testing.T) {
+	if got := maxDrawdown(nil); got != 0 {
+		t.Errorf("maxDrawdown(nil) = %v, want 0", got)
+	}
+}
+
+func TestSharpeRatioInsufficientData(t *testing.T) {
+	if got := sharpeRatio([]EquityPoint{{Equity: 100}}); got != 0 {
+		t.Errorf("sharpeRatio with <2 points = %v, want 0", got)
+	}
+}
+
+func TestSharpeRatioZeroVariance(t *testing.T) {
+	// Identical 10% returns every step give zero variance, and the stddev
+	// guard returns 0 rather than dividing by zero.
+	curve := []EquityPoint{{Equity: 100}, {Equity: 110}, {Equity: 121}}
+	if got := sharpeRatio(curve); got != 0 {
+		t.Errorf("sharpeRatio with zero-variance returns = %v, want 0", got)
+	}
+}
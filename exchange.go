@@ -0,0 +1,185 @@
+package main
+
+import (
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"go_auto_trade/exchange"
+)
+
+// Exchange abstracts the calls the trading loop needs to make for a symbol,
+// so the same strategy logic in tradePortfolioOnce can run against a live
+// multi-venue portfolio or a historical backtest without modification.
+type Exchange interface {
+	GetKlines(symbol, interval string, limit int) []Kline
+	GetCurrentPrice(symbol string) float64
+	GetBalance(symbol, asset string) float64
+	PlaceMarketOrder(symbol, side string, quantity float64) (avgPrice, filledQty float64)
+	Get24hVolume(symbol string) float64
+	// BaseAsset returns the asset balances are held in once a position is
+	// opened (e.g. "BTC" for "BTCUSDT"), so callers never need to hard-code a
+	// "strip the quote currency" rule themselves.
+	BaseAsset(symbol string) string
+}
+
+// volumeProvider is implemented by exchange.Exchange adapters that can report
+// 24h quote volume (currently just Binance); adapters without it are treated
+// as always passing the liquidity filter.
+type volumeProvider interface {
+	Get24hQuoteVolume(symbol exchange.Symbol) (float64, error)
+}
+
+// LiveExchange implements Exchange by routing each symbol to its configured
+// exchange.Exchange session, so the autotrader loop stays exchange-agnostic
+// and a symbol traded on Bitget costs the same code path as one on Binance.
+type LiveExchange struct {
+	refreshInterval int64
+	sessions        map[string]exchange.Exchange
+	symbolSession   map[string]string
+	symbols         map[string]exchange.Symbol
+	store           *MarketDataStore // optional; nil means every symbol is REST-polled
+
+	mu             sync.Mutex
+	klineCache     map[string][]Kline
+	klineCacheTime map[string]int64
+}
+
+func NewLiveExchange(
+	refreshInterval int64,
+	sessions map[string]exchange.Exchange,
+	symbolSession map[string]string,
+	symbols map[string]exchange.Symbol,
+	store *MarketDataStore,
+) *LiveExchange {
+	return &LiveExchange{
+		refreshInterval: refreshInterval,
+		sessions:        sessions,
+		symbolSession:   symbolSession,
+		symbols:         symbols,
+		store:           store,
+		klineCache:      make(map[string][]Kline),
+		klineCacheTime:  make(map[string]int64),
+	}
+}
+
+func (e *LiveExchange) sessionFor(symbol string) exchange.Exchange {
+	name, ok := e.symbolSession[symbol]
+	if !ok {
+		log.Printf("no session configured for %s", symbol)
+		return nil
+	}
+	return e.sessions[name]
+}
+
+// symbolFor returns the routed Symbol with its tick sizes, falling back to a
+// zero-tick-size split on the trailing "USDT" for symbols not present in the
+// routing config.
+func (e *LiveExchange) symbolFor(symbol string) exchange.Symbol {
+	if sym, ok := e.symbols[symbol]; ok {
+		return sym
+	}
+	return exchange.Symbol{Base: symbol[:len(symbol)-4], Quote: "USDT"}
+}
+
+// GetKlines prefers the streamed MarketDataStore when it has data for
+// symbol, falling back to the REST-cached path below when the store is
+// unset, not yet populated, or the symbol isn't routed to a streaming
+// session (e.g. it's on an adapter without StreamProvider support).
+func (e *LiveExchange) GetKlines(symbol, interval string, limit int) []Kline {
+	if e.store != nil {
+		if kl := e.store.Klines(symbol); len(kl) > 0 {
+			if len(kl) > limit {
+				kl = kl[len(kl)-limit:]
+			}
+			return kl
+		}
+	}
+
+	e.mu.Lock()
+	cacheKey := symbol + "_" + interval
+	now := time.Now().Unix()
+	if kl, ok := e.klineCache[cacheKey]; ok && now-e.klineCacheTime[cacheKey] < e.refreshInterval {
+		e.mu.Unlock()
+		return kl
+	}
+	e.mu.Unlock()
+
+	sess := e.sessionFor(symbol)
+	if sess == nil {
+		return nil
+	}
+	klines, err := sess.GetKlines(e.symbolFor(symbol), interval, limit)
+	if err != nil {
+		log.Printf("GetKlines failed for %s: %v", symbol, err)
+		return nil
+	}
+	e.mu.Lock()
+	e.klineCache[cacheKey] = klines
+	e.klineCacheTime[cacheKey] = now
+	e.mu.Unlock()
+	return klines
+}
+
+func (e *LiveExchange) GetCurrentPrice(symbol string) float64 {
+	if e.store != nil {
+		if p, ok := e.store.Price(symbol); ok {
+			return p
+		}
+	}
+	sess := e.sessionFor(symbol)
+	if sess == nil {
+		return 0
+	}
+	price, err := sess.GetTicker(e.symbolFor(symbol))
+	if err != nil {
+		log.Printf("GetTicker failed for %s: %v", symbol, err)
+		return 0
+	}
+	return price
+}
+
+func (e *LiveExchange) GetBalance(symbol, asset string) float64 {
+	sess := e.sessionFor(symbol)
+	if sess == nil {
+		return 0
+	}
+	bal, err := sess.GetBalance(asset)
+	if err != nil {
+		log.Printf("GetBalance(%s) failed for %s: %v", asset, symbol, err)
+		return 0
+	}
+	return bal
+}
+
+func (e *LiveExchange) PlaceMarketOrder(symbol, side string, quantity float64) (float64, float64) {
+	sess := e.sessionFor(symbol)
+	if sess == nil {
+		return 0, 0
+	}
+	order, err := sess.PlaceMarketOrder(e.symbolFor(symbol), side, quantity)
+	if err != nil {
+		log.Printf("PlaceMarketOrder failed for %s %s: %v", side, symbol, err)
+		return 0, 0
+	}
+	return order.AvgPrice, order.FilledQty
+}
+
+func (e *LiveExchange) Get24hVolume(symbol string) float64 {
+	sess := e.sessionFor(symbol)
+	vp, ok := sess.(volumeProvider)
+	if !ok {
+		return math.MaxFloat64
+	}
+	vol, err := vp.Get24hQuoteVolume(e.symbolFor(symbol))
+	if err != nil {
+		log.Printf("Get24hQuoteVolume failed for %s: %v", symbol, err)
+		return 0
+	}
+	return vol
+}
+
+func (e *LiveExchange) BaseAsset(symbol string) string {
+	return e.symbolFor(symbol).Base
+}
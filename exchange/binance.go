@@ -0,0 +1,339 @@
+package exchange
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Binance implements Exchange against the Binance spot REST API.
+type Binance struct {
+	APIKey    string
+	APISecret string
+	BaseURL   string
+	Client    *http.Client
+}
+
+func NewBinance(apiKey, apiSecret string) *Binance {
+	return &Binance{
+		APIKey:    apiKey,
+		APISecret: apiSecret,
+		BaseURL:   "https://api.binance.com",
+		Client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (b *Binance) Ping() error {
+	resp, err := b.Client.Get(b.BaseURL + "/api/v3/ping")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ping failed, status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func (b *Binance) GetKlines(symbol Symbol, interval string, limit int) ([]Kline, error) {
+	u := b.BaseURL + "/api/v3/klines?symbol=" + symbol.String() + "&interval=" + interval + "&limit=" + strconv.Itoa(limit)
+	return b.getKlinesURL(u)
+}
+
+// GetKlinesRange pages through the klines endpoint between startMs and endMs,
+// since a single request is capped at 1000 rows. It's used by the backtest
+// engine to load a full historical window up front.
+func (b *Binance) GetKlinesRange(symbol Symbol, interval string, startMs, endMs int64) ([]Kline, error) {
+	var out []Kline
+	cursor := startMs
+	for cursor < endMs {
+		u := b.BaseURL + "/api/v3/klines?symbol=" + symbol.String() + "&interval=" + interval +
+			"&startTime=" + strconv.FormatInt(cursor, 10) +
+			"&endTime=" + strconv.FormatInt(endMs, 10) +
+			"&limit=1000"
+		page, err := b.getKlinesURL(u)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		out = append(out, page...)
+		lastOpen := page[len(page)-1].OpenTimeMs
+		if lastOpen <= cursor {
+			break
+		}
+		cursor = lastOpen + 1
+		if len(page) < 1000 {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (b *Binance) getKlinesURL(u string) ([]Kline, error) {
+	arr := b.safeGetArray(u)
+	if arr == nil {
+		return nil, fmt.Errorf("klines request failed: %s", u)
+	}
+	var klines []Kline
+	for _, v := range arr {
+		vv, ok := v.([]interface{})
+		if !ok || len(vv) < 5 {
+			continue
+		}
+		ot, _ := vv[0].(float64)
+		ops, _ := vv[1].(string)
+		hs, _ := vv[2].(string)
+		ls, _ := vv[3].(string)
+		cs, _ := vv[4].(string)
+		of, _ := strconv.ParseFloat(ops, 64)
+		hf, _ := strconv.ParseFloat(hs, 64)
+		lf, _ := strconv.ParseFloat(ls, 64)
+		cf, err := strconv.ParseFloat(cs, 64)
+		if err == nil {
+			klines = append(klines, Kline{OpenTimeMs: int64(ot), Open: of, High: hf, Low: lf, Close: cf})
+		}
+	}
+	return klines, nil
+}
+
+func (b *Binance) GetTicker(symbol Symbol) (float64, error) {
+	u := b.BaseURL + "/api/v3/ticker/price?symbol=" + symbol.String()
+	d := b.safeGet(u)
+	if d == nil {
+		return 0, fmt.Errorf("ticker request failed for %s", symbol)
+	}
+	ps, ok := d["price"].(string)
+	if !ok {
+		return 0, fmt.Errorf("ticker response missing price for %s", symbol)
+	}
+	return strconv.ParseFloat(ps, 64)
+}
+
+// GetOrderBook returns the current bid/ask depth for symbol, used by the
+// triangular arbitrage strategy to compute implied cross-rates.
+func (b *Binance) GetOrderBook(symbol Symbol, limit int) (*OrderBook, error) {
+	u := b.BaseURL + "/api/v3/depth?symbol=" + symbol.String() + "&limit=" + strconv.Itoa(limit)
+	d := b.safeGet(u)
+	if d == nil {
+		return nil, fmt.Errorf("depth request failed for %s", symbol)
+	}
+	bidsRaw, _ := d["bids"].([]interface{})
+	asksRaw, _ := d["asks"].([]interface{})
+	return &OrderBook{Bids: parsePriceLevels(bidsRaw), Asks: parsePriceLevels(asksRaw)}, nil
+}
+
+func parsePriceLevels(rows []interface{}) []PriceLevel {
+	levels := make([]PriceLevel, 0, len(rows))
+	for _, row := range rows {
+		pair, ok := row.([]interface{})
+		if !ok || len(pair) < 2 {
+			continue
+		}
+		p, _ := strconv.ParseFloat(fmt.Sprint(pair[0]), 64)
+		q, _ := strconv.ParseFloat(fmt.Sprint(pair[1]), 64)
+		levels = append(levels, PriceLevel{Price: p, Qty: q})
+	}
+	return levels
+}
+
+// Get24hQuoteVolume returns the 24h quote-asset trading volume, used by the
+// autotrader loop's liquidity filter. It's Binance-specific and not part of
+// the Exchange interface.
+func (b *Binance) Get24hQuoteVolume(symbol Symbol) (float64, error) {
+	u := b.BaseURL + "/api/v3/ticker/24hr?symbol=" + symbol.String()
+	d := b.safeGet(u)
+	if d == nil {
+		return 0, fmt.Errorf("24hr ticker request failed for %s", symbol)
+	}
+	qv, ok := d["quoteVolume"].(string)
+	if !ok {
+		return 0, fmt.Errorf("24hr ticker response missing quoteVolume for %s", symbol)
+	}
+	return strconv.ParseFloat(qv, 64)
+}
+
+func (b *Binance) GetBalance(asset string) (float64, error) {
+	t := time.Now().UnixMilli()
+	q := "timestamp=" + strconv.FormatInt(t, 10)
+	sign := sign(q, b.APISecret)
+	u := b.BaseURL + "/api/v3/account?" + q + "&signature=" + sign
+	resp := b.safeGetWithHeaders(u, b.authHeaders())
+	if resp == nil {
+		return 0, fmt.Errorf("account request failed")
+	}
+	var bResp struct {
+		Balances []struct {
+			Asset string `json:"asset"`
+			Free  string `json:"free"`
+		} `json:"balances"`
+	}
+	j, _ := json.Marshal(resp)
+	json.Unmarshal(j, &bResp)
+	for _, bal := range bResp.Balances {
+		if bal.Asset == asset {
+			return strconv.ParseFloat(bal.Free, 64)
+		}
+	}
+	return 0, nil
+}
+
+func (b *Binance) PlaceMarketOrder(symbol Symbol, side string, qty float64) (*Order, error) {
+	qty = RoundAmount(qty, symbol)
+	ts := time.Now().UnixMilli()
+	qs := "symbol=" + symbol.String() + "&side=" + side + "&type=MARKET&quantity=" + strconv.FormatFloat(qty, 'f', -1, 64) +
+		"&timestamp=" + strconv.FormatInt(ts, 10)
+	u := b.BaseURL + "/api/v3/order?" + qs + "&signature=" + sign(qs, b.APISecret)
+	r := b.safePostWithHeaders(u, b.authHeaders())
+	if r == nil {
+		return nil, fmt.Errorf("order response is nil for %s %s", symbol, side)
+	}
+	var o struct {
+		OrderID int64  `json:"orderId"`
+		Code    int    `json:"code"`
+		Msg     string `json:"msg"`
+		Fills   []struct {
+			Price string `json:"price"`
+			Qty   string `json:"qty"`
+		} `json:"fills"`
+	}
+	j, _ := json.Marshal(r)
+	json.Unmarshal(j, &o)
+	if o.Code < 0 {
+		return nil, fmt.Errorf("binance error code: %d, msg: %s", o.Code, o.Msg)
+	}
+	var totalCost, totalQty float64
+	for _, f := range o.Fills {
+		p, _ := strconv.ParseFloat(f.Price, 64)
+		q, _ := strconv.ParseFloat(f.Qty, 64)
+		totalCost += p * q
+		totalQty += q
+	}
+	var avgPrice float64
+	if totalQty > 0 {
+		avgPrice = totalCost / totalQty
+	}
+	return &Order{
+		ID: strconv.FormatInt(o.OrderID, 10), Symbol: symbol, Side: side,
+		Qty: qty, FilledQty: totalQty, AvgPrice: avgPrice, Status: "FILLED",
+	}, nil
+}
+
+func (b *Binance) GetOrder(symbol Symbol, id string) (*Order, error) {
+	q := "symbol=" + symbol.String() + "&orderId=" + id + "&timestamp=" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	u := b.BaseURL + "/api/v3/order?" + q + "&signature=" + sign(q, b.APISecret)
+	r := b.safeGetWithHeaders(u, b.authHeaders())
+	if r == nil {
+		return nil, fmt.Errorf("get order failed for %s %s", symbol, id)
+	}
+	status, _ := r["status"].(string)
+	return &Order{ID: id, Symbol: symbol, Status: status}, nil
+}
+
+func (b *Binance) CancelOrder(symbol Symbol, id string) error {
+	q := "symbol=" + symbol.String() + "&orderId=" + id + "&timestamp=" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	u := b.BaseURL + "/api/v3/order?" + q + "&signature=" + sign(q, b.APISecret)
+	req, _ := http.NewRequest("DELETE", u, nil)
+	for k, v := range b.authHeaders() {
+		req.Header.Set(k, v)
+	}
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("cancel order failed, status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *Binance) authHeaders() map[string]string {
+	return map[string]string{"X-MBX-APIKEY": b.APIKey}
+}
+
+func sign(query, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(query))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (b *Binance) safeGet(url string) map[string]interface{} {
+	for i := 0; i < 3; i++ {
+		resp, err := b.Client.Get(url)
+		if err == nil && resp.StatusCode == 200 {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			var result map[string]interface{}
+			if json.Unmarshal(body, &result) == nil && result != nil {
+				return result
+			}
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return nil
+}
+
+func (b *Binance) safeGetArray(url string) []interface{} {
+	for i := 0; i < 3; i++ {
+		resp, err := b.Client.Get(url)
+		if err == nil && resp.StatusCode == 200 {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			var result []interface{}
+			if json.Unmarshal(body, &result) == nil {
+				return result
+			}
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return nil
+}
+
+func (b *Binance) safeGetWithHeaders(url string, headers map[string]string) map[string]interface{} {
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest("GET", url, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := b.Client.Do(req)
+		if err == nil && resp.StatusCode == 200 {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			var r map[string]interface{}
+			if json.Unmarshal(body, &r) == nil && r != nil {
+				return r
+			}
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return nil
+}
+
+func (b *Binance) safePostWithHeaders(url string, headers map[string]string) map[string]interface{} {
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest("POST", url, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := b.Client.Do(req)
+		if err == nil && (resp.StatusCode == 200 || resp.StatusCode == 201) {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			var r map[string]interface{}
+			if json.Unmarshal(body, &r) == nil && r != nil {
+				return r
+			}
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package exchange
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bitget implements Exchange against the Bitget spot v2 REST API. Its request
+// signing differs from Binance: signatures are base64(HMAC-SHA256(secret,
+// timestamp+method+path+body)) sent via ACCESS-* headers, and orders are
+// keyed by size rather than quantity.
+type Bitget struct {
+	APIKey     string
+	APISecret  string
+	Passphrase string
+	BaseURL    string
+	Client     *http.Client
+}
+
+func NewBitget(apiKey, apiSecret, passphrase string) *Bitget {
+	return &Bitget{
+		APIKey:     apiKey,
+		APISecret:  apiSecret,
+		Passphrase: passphrase,
+		BaseURL:    "https://api.bitget.com",
+		Client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (g *Bitget) Ping() error {
+	resp, err := g.Client.Get(g.BaseURL + "/api/v2/public/time")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ping failed, status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// bitgetGranularity maps the Binance-style interval strings used elsewhere in
+// this codebase (e.g. "15m", "1h", "1d") to the candlestick granularity
+// Bitget's v2 spot API expects (e.g. "15min", "1h", "1day"). Anything not
+// listed is passed through unchanged so a bad interval surfaces as a Bitget
+// API error instead of being silently swallowed here.
+func bitgetGranularity(interval string) string {
+	switch interval {
+	case "1m":
+		return "1min"
+	case "3m":
+		return "3min"
+	case "5m":
+		return "5min"
+	case "15m":
+		return "15min"
+	case "30m":
+		return "30min"
+	case "1d":
+		return "1day"
+	case "3d":
+		return "3day"
+	case "1w":
+		return "1week"
+	default:
+		return interval
+	}
+}
+
+func (g *Bitget) GetKlines(symbol Symbol, interval string, limit int) ([]Kline, error) {
+	path := "/api/v2/spot/market/candles?symbol=" + symbol.String() +
+		"&granularity=" + bitgetGranularity(interval) + "&limit=" + strconv.Itoa(limit)
+	data, err := g.getArray(path)
+	if err != nil {
+		return nil, err
+	}
+	var klines []Kline
+	for _, v := range data {
+		row, ok := v.([]interface{})
+		if !ok || len(row) < 5 {
+			continue
+		}
+		ot, _ := strconv.ParseInt(fmt.Sprint(row[0]), 10, 64)
+		of, _ := strconv.ParseFloat(fmt.Sprint(row[1]), 64)
+		hf, _ := strconv.ParseFloat(fmt.Sprint(row[2]), 64)
+		lf, _ := strconv.ParseFloat(fmt.Sprint(row[3]), 64)
+		cf, _ := strconv.ParseFloat(fmt.Sprint(row[4]), 64)
+		klines = append(klines, Kline{OpenTimeMs: ot, Open: of, High: hf, Low: lf, Close: cf})
+	}
+	return klines, nil
+}
+
+func (g *Bitget) GetTicker(symbol Symbol) (float64, error) {
+	path := "/api/v2/spot/market/tickers?symbol=" + symbol.String()
+	data, err := g.getArray(path)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, fmt.Errorf("no ticker data for %s", symbol)
+	}
+	row, ok := data[0].(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("unexpected ticker shape for %s", symbol)
+	}
+	return strconv.ParseFloat(fmt.Sprint(row["lastPr"]), 64)
+}
+
+// GetOrderBook returns the current bid/ask depth for symbol, used by the
+// triangular arbitrage strategy to compute implied cross-rates.
+func (g *Bitget) GetOrderBook(symbol Symbol, limit int) (*OrderBook, error) {
+	path := "/api/v2/spot/market/orderbook?symbol=" + symbol.String() + "&limit=" + strconv.Itoa(limit)
+	resp, err := g.Client.Get(g.BaseURL + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	var wrapper struct {
+		Data struct {
+			Bids [][]string `json:"bids"`
+			Asks [][]string `json:"asks"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil, err
+	}
+	return &OrderBook{Bids: levelsFromStrings(wrapper.Data.Bids), Asks: levelsFromStrings(wrapper.Data.Asks)}, nil
+}
+
+func levelsFromStrings(rows [][]string) []PriceLevel {
+	levels := make([]PriceLevel, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		p, _ := strconv.ParseFloat(row[0], 64)
+		q, _ := strconv.ParseFloat(row[1], 64)
+		levels = append(levels, PriceLevel{Price: p, Qty: q})
+	}
+	return levels
+}
+
+func (g *Bitget) GetBalance(asset string) (float64, error) {
+	resp, err := g.signedRequest("GET", "/api/v2/spot/account/assets", nil)
+	if err != nil {
+		return 0, err
+	}
+	data, _ := resp["data"].([]interface{})
+	for _, v := range data {
+		row, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if fmt.Sprint(row["coin"]) == asset {
+			return strconv.ParseFloat(fmt.Sprint(row["available"]), 64)
+		}
+	}
+	return 0, nil
+}
+
+func (g *Bitget) PlaceMarketOrder(symbol Symbol, side string, qty float64) (*Order, error) {
+	qty = RoundAmount(qty, symbol)
+	size := qty
+	if side == "BUY" {
+		// Unlike every other side/venue in this codebase, where qty is
+		// always a base-asset quantity, Bitget's spot market-buy size is
+		// denominated in the quote asset. Convert using the current price
+		// before submitting so a BUY doesn't place an order sized as if qty
+		// base units of quote currency were being spent.
+		price, err := g.GetTicker(symbol)
+		if err != nil {
+			return nil, fmt.Errorf("get ticker for market buy size: %w", err)
+		}
+		size = qty * price
+	}
+	body := map[string]interface{}{
+		"symbol":    symbol.String(),
+		"side":      toBitgetSide(side),
+		"orderType": "market",
+		"size":      strconv.FormatFloat(size, 'f', -1, 64),
+	}
+	resp, err := g.signedRequest("POST", "/api/v2/spot/trade/place-order", body)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := resp["data"].(map[string]interface{})
+	id := fmt.Sprint(data["orderId"])
+
+	// The place-order response carries no fill data, so the average fill
+	// price has to be read back via orderInfo. Fall back to the last
+	// traded price if that lookup fails, so callers never see a zero
+	// AvgPrice for a filled market order.
+	avgPrice, filledQty := qty, qty
+	if o, err := g.GetOrder(symbol, id); err == nil && o.AvgPrice > 0 {
+		avgPrice = o.AvgPrice
+		if o.FilledQty > 0 {
+			filledQty = o.FilledQty
+		}
+	} else if p, err := g.GetTicker(symbol); err == nil {
+		avgPrice = p
+	}
+
+	return &Order{
+		ID: id, Symbol: symbol, Side: side,
+		Qty: qty, FilledQty: filledQty, AvgPrice: avgPrice, Status: "FILLED",
+	}, nil
+}
+
+func (g *Bitget) GetOrder(symbol Symbol, id string) (*Order, error) {
+	path := "/api/v2/spot/trade/orderInfo?symbol=" + symbol.String() + "&orderId=" + id
+	resp, err := g.signedRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := resp["data"].([]interface{})
+	if len(data) == 0 {
+		return nil, fmt.Errorf("order %s not found for %s", id, symbol)
+	}
+	row, _ := data[0].(map[string]interface{})
+	avgPrice, _ := strconv.ParseFloat(fmt.Sprint(row["priceAvg"]), 64)
+	filledQty, _ := strconv.ParseFloat(fmt.Sprint(row["baseVolume"]), 64)
+	return &Order{
+		ID: id, Symbol: symbol, Status: fmt.Sprint(row["status"]),
+		AvgPrice: avgPrice, FilledQty: filledQty,
+	}, nil
+}
+
+func (g *Bitget) CancelOrder(symbol Symbol, id string) error {
+	body := map[string]interface{}{"symbol": symbol.String(), "orderId": id}
+	_, err := g.signedRequest("POST", "/api/v2/spot/trade/cancel-order", body)
+	return err
+}
+
+func toBitgetSide(side string) string {
+	if side == "BUY" {
+		return "buy"
+	}
+	return "sell"
+}
+
+func (g *Bitget) getArray(path string) ([]interface{}, error) {
+	resp, err := g.Client.Get(g.BaseURL + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	var wrapper struct {
+		Data []interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper.Data, nil
+}
+
+func (g *Bitget) signedRequest(method, path string, body map[string]interface{}) (map[string]interface{}, error) {
+	var bodyStr string
+	if body != nil {
+		b, _ := json.Marshal(body)
+		bodyStr = string(b)
+	}
+	ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(g.APISecret))
+	mac.Write([]byte(ts + method + path + bodyStr))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	req, err := http.NewRequest(method, g.BaseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if bodyStr != "" {
+		req, err = http.NewRequest(method, g.BaseURL+path, strings.NewReader(bodyStr))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("ACCESS-KEY", g.APIKey)
+	req.Header.Set("ACCESS-SIGN", signature)
+	req.Header.Set("ACCESS-TIMESTAMP", ts)
+	req.Header.Set("ACCESS-PASSPHRASE", g.Passphrase)
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	var result map[string]interface{}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, err
+	}
+	if code, ok := result["code"].(string); ok && code != "00000" {
+		return nil, fmt.Errorf("bitget error %s: %v", code, result["msg"])
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+// Package exchange defines a venue-agnostic trading interface plus adapters
+// for the concrete exchanges the bot can trade on. New venues are added by
+// implementing Exchange, not by touching the autotrader loop.
+package exchange
+
+import "math"
+
+// Symbol is a currency pair together with the venue's tick sizes, so callers
+// can round order quantity/price correctly instead of hard-coding a fixed
+// number of decimals per symbol.
+type Symbol struct {
+	Base           string
+	Quote          string
+	AmountTickSize float64
+	PriceTickSize  float64
+}
+
+// String returns the concatenated "BASEQUOTE" form most venues expect on the
+// wire (e.g. "BTCUSDT").
+func (s Symbol) String() string {
+	return s.Base + s.Quote
+}
+
+// RoundAmount floors qty down to the symbol's amount tick size.
+func RoundAmount(qty float64, sym Symbol) float64 {
+	return roundToTick(qty, sym.AmountTickSize)
+}
+
+// RoundPrice floors price down to the symbol's price tick size.
+func RoundPrice(price float64, sym Symbol) float64 {
+	return roundToTick(price, sym.PriceTickSize)
+}
+
+func roundToTick(val, tick float64) float64 {
+	if tick <= 0 {
+		return val
+	}
+	return math.Floor(val/tick) * tick
+}
+
+// Kline is one candle's OHLC data.
+type Kline struct {
+	OpenTimeMs int64
+	Open       float64
+	High       float64
+	Low        float64
+	Close      float64
+}
+
+// PriceLevel is one row of an order book: a price and the quantity resting
+// there.
+type PriceLevel struct {
+	Price float64
+	Qty   float64
+}
+
+// OrderBook is a symbol's current bid/ask depth, best price first.
+type OrderBook struct {
+	Bids []PriceLevel
+	Asks []PriceLevel
+}
+
+// BestBid returns the top of book bid price and whether one exists.
+func (ob *OrderBook) BestBid() (float64, bool) {
+	if ob == nil || len(ob.Bids) == 0 {
+		return 0, false
+	}
+	return ob.Bids[0].Price, true
+}
+
+// BestAsk returns the top of book ask price and whether one exists.
+func (ob *OrderBook) BestAsk() (float64, bool) {
+	if ob == nil || len(ob.Asks) == 0 {
+		return 0, false
+	}
+	return ob.Asks[0].Price, true
+}
+
+// Order is the normalized result of placing or querying an order.
+type Order struct {
+	ID        string
+	Symbol    Symbol
+	Side      string
+	Price     float64
+	Qty       float64
+	FilledQty float64
+	AvgPrice  float64
+	Status    string
+}
+
+// Exchange abstracts the calls the bot needs from a trading venue so the
+// autotrader loop can route each symbol to its configured session without
+// caring whether it's talking to Binance, Bitget, or anything else.
+type Exchange interface {
+	Ping() error
+	GetKlines(symbol Symbol, interval string, limit int) ([]Kline, error)
+	GetTicker(symbol Symbol) (float64, error)
+	GetOrderBook(symbol Symbol, limit int) (*OrderBook, error)
+	GetBalance(asset string) (float64, error)
+	PlaceMarketOrder(symbol Symbol, side string, qty float64) (*Order, error)
+	GetOrder(symbol Symbol, id string) (*Order, error)
+	CancelOrder(symbol Symbol, id string) error
+}
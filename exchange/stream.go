@@ -0,0 +1,187 @@
+package exchange
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// KlineEvent is emitted for every kline update on a streamed symbol. Closed
+// reports whether the candle is final, so callers can tell an in-progress
+// tick from a candle-close event.
+type KlineEvent struct {
+	Symbol string
+	Kline  Kline
+	Closed bool
+}
+
+// TickerEvent is emitted for every last-traded-price update on a streamed
+// symbol.
+type TickerEvent struct {
+	Symbol string
+	Price  float64
+}
+
+// StreamProvider is implemented by Exchange adapters that can push kline and
+// ticker updates over a WebSocket instead of being polled over REST. Symbols
+// routed to an adapter without this capability keep being polled.
+type StreamProvider interface {
+	// StreamKlines subscribes to symbols and forwards decoded events on
+	// klineEvents/tickerEvents until stop is closed. It blocks, reconnecting
+	// internally on any error, so callers should run it in its own
+	// goroutine.
+	StreamKlines(symbols []string, interval string, klineEvents chan<- KlineEvent, tickerEvents chan<- TickerEvent, stop <-chan struct{})
+}
+
+// StreamKlines subscribes to Binance's combined <symbol>@kline_<interval>
+// and <symbol>@ticker streams for symbols and forwards decoded events until
+// stop is closed. It auto-reconnects with exponential backoff on any
+// dial/read error, mirroring the streaming subsystems in the goex/bbgo
+// ecosystem.
+func (b *Binance) StreamKlines(symbols []string, interval string, klineEvents chan<- KlineEvent, tickerEvents chan<- TickerEvent, stop <-chan struct{}) {
+	backoff := time.Second
+	const maxBackoff = 60 * time.Second
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if err := b.streamOnce(symbols, interval, klineEvents, tickerEvents, stop); err != nil {
+			log.Printf("market data stream error, reconnecting in %s: %v", backoff, err)
+			select {
+			case <-time.After(backoff):
+			case <-stop:
+				return
+			}
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+		backoff = time.Second
+	}
+}
+
+func (b *Binance) streamOnce(symbols []string, interval string, klineEvents chan<- KlineEvent, tickerEvents chan<- TickerEvent, stop <-chan struct{}) error {
+	streams := make([]string, 0, len(symbols)*2)
+	for _, sym := range symbols {
+		lower := strings.ToLower(sym)
+		streams = append(streams, lower+"@kline_"+interval, lower+"@ticker")
+	}
+	u := url.URL{
+		Scheme:   "wss",
+		Host:     "stream.binance.com:9443",
+		Path:     "/stream",
+		RawQuery: "streams=" + strings.Join(streams, "/"),
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		select {
+		case <-stop:
+			conn.Close()
+		case <-closed:
+		}
+	}()
+	defer close(closed)
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-stop:
+				return nil
+			default:
+				return fmt.Errorf("read: %w", err)
+			}
+		}
+		raw, err = maybeGunzip(raw)
+		if err != nil {
+			log.Printf("failed to decompress market data frame: %v", err)
+			continue
+		}
+		dispatchStreamFrame(raw, klineEvents, tickerEvents)
+	}
+}
+
+// maybeGunzip decompresses raw if it looks like a gzip member (Binance's WS
+// frames are plain JSON today, but combined-stream payloads have carried
+// gzip in the past, so frames are sniffed rather than assumed).
+func maybeGunzip(raw []byte) ([]byte, error) {
+	if len(raw) < 2 || raw[0] != 0x1f || raw[1] != 0x8b {
+		return raw, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func dispatchStreamFrame(raw []byte, klineEvents chan<- KlineEvent, tickerEvents chan<- TickerEvent) {
+	var frame struct {
+		Stream string          `json:"stream"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &frame); err != nil || frame.Stream == "" {
+		return
+	}
+	switch {
+	case strings.Contains(frame.Stream, "@kline_"):
+		var payload struct {
+			Symbol string `json:"s"`
+			K      struct {
+				OpenTimeMs int64  `json:"t"`
+				Open       string `json:"o"`
+				High       string `json:"h"`
+				Low        string `json:"l"`
+				Close      string `json:"c"`
+				Closed     bool   `json:"x"`
+			} `json:"k"`
+		}
+		if err := json.Unmarshal(frame.Data, &payload); err != nil {
+			return
+		}
+		of, _ := strconv.ParseFloat(payload.K.Open, 64)
+		hf, _ := strconv.ParseFloat(payload.K.High, 64)
+		lf, _ := strconv.ParseFloat(payload.K.Low, 64)
+		cf, err := strconv.ParseFloat(payload.K.Close, 64)
+		if err != nil {
+			return
+		}
+		klineEvents <- KlineEvent{
+			Symbol: payload.Symbol,
+			Kline:  Kline{OpenTimeMs: payload.K.OpenTimeMs, Open: of, High: hf, Low: lf, Close: cf},
+			Closed: payload.K.Closed,
+		}
+	case strings.Contains(frame.Stream, "@ticker"):
+		var payload struct {
+			Symbol string `json:"s"`
+			Last   string `json:"c"`
+		}
+		if err := json.Unmarshal(frame.Data, &payload); err != nil {
+			return
+		}
+		price, err := strconv.ParseFloat(payload.Last, 64)
+		if err != nil {
+			return
+		}
+		tickerEvents <- TickerEvent{Symbol: payload.Symbol, Price: price}
+	}
+}
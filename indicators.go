@@ -0,0 +1,92 @@
+package main
+
+// IncrementalEMA maintains an exponential moving average that updates in
+// O(1) per new sample instead of rescanning history, for use on the
+// event-driven candle-close path fed by MarketDataStore. The first `window`
+// samples are averaged to seed the EMA; every sample after that is a single
+// multiply-add.
+type IncrementalEMA struct {
+	window    int
+	k         float64
+	value     float64
+	seeded    bool
+	seedSum   float64
+	seedCount int
+}
+
+func NewIncrementalEMA(window int) *IncrementalEMA {
+	return &IncrementalEMA{window: window, k: 2.0 / (float64(window) + 1)}
+}
+
+// Update feeds one new sample and returns the updated EMA value, or ok=false
+// while still accumulating the initial seed window.
+func (e *IncrementalEMA) Update(x float64) (value float64, ok bool) {
+	if !e.seeded {
+		e.seedSum += x
+		e.seedCount++
+		if e.seedCount < e.window {
+			return 0, false
+		}
+		e.value = e.seedSum / float64(e.window)
+		e.seeded = true
+		return e.value, true
+	}
+	e.value = x*e.k + e.value*(1-e.k)
+	return e.value, true
+}
+
+// IncrementalRSI maintains Wilder-smoothed average gain/loss so RSI updates
+// in O(1) per new close, mirroring the Wilder smoothing calcATR already uses
+// for ATR in this package.
+type IncrementalRSI struct {
+	period    int
+	prevClose float64
+	hasPrev   bool
+	seeded    bool
+	seedGain  float64
+	seedLoss  float64
+	seedCount int
+	avgGain   float64
+	avgLoss   float64
+}
+
+func NewIncrementalRSI(period int) *IncrementalRSI {
+	return &IncrementalRSI{period: period}
+}
+
+// Update feeds one new close and returns the updated RSI value, or ok=false
+// while still accumulating the first close or the initial seed window.
+func (r *IncrementalRSI) Update(closePrice float64) (value float64, ok bool) {
+	if !r.hasPrev {
+		r.prevClose = closePrice
+		r.hasPrev = true
+		return 0, false
+	}
+	diff := closePrice - r.prevClose
+	r.prevClose = closePrice
+	var gain, loss float64
+	if diff > 0 {
+		gain = diff
+	} else {
+		loss = -diff
+	}
+	if !r.seeded {
+		r.seedGain += gain
+		r.seedLoss += loss
+		r.seedCount++
+		if r.seedCount < r.period {
+			return 0, false
+		}
+		r.avgGain = r.seedGain / float64(r.period)
+		r.avgLoss = r.seedLoss / float64(r.period)
+		r.seeded = true
+	} else {
+		r.avgGain = (r.avgGain*float64(r.period-1) + gain) / float64(r.period)
+		r.avgLoss = (r.avgLoss*float64(r.period-1) + loss) / float64(r.period)
+	}
+	if r.avgLoss == 0 {
+		return 100, true
+	}
+	rs := r.avgGain / r.avgLoss
+	return 100 - (100 / (1 + rs)), true
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestIncrementalRSIAllGains(t *testing.T) {
+	r := NewIncrementalRSI(3)
+	closes := []float64{10, 11, 12, 13, 14}
+	var last float64
+	var ok bool
+	for _, c := range closes {
+		last, ok = r.Update(c)
+	}
+	if !ok {
+		t.Fatalf("expected RSI to be ready after %d closes", len(closes))
+	}
+	if last != 100 {
+		t.Errorf("all-gains RSI = %v, want 100", last)
+	}
+}
+
+func TestIncrementalRSIAllLosses(t *testing.T) {
+	r := NewIncrementalRSI(3)
+	closes := []float64{14, 13, 12, 11, 10}
+	var last float64
+	for _, c := range closes {
+		last, _ = r.Update(c)
+	}
+	if last != 0 {
+		t.Errorf("all-losses RSI = %v, want 0", last)
+	}
+}
+
+// TestIncrementalRSINotReadyDuringSeed checks the Wilder seeding window: the
+// first Update just captures prevClose, and the next `period` calls only
+// accumulate seedGain/seedLoss, so ok stays false until the (period+1)th
+// call.
+func TestIncrementalRSINotReadyDuringSeed(t *testing.T) {
+	r := NewIncrementalRSI(14)
+	for i := 0; i < 14; i++ {
+		if _, ok := r.Update(float64(100 + i)); ok {
+			t.Fatalf("got ok=true before the period=14 seed window filled, at call %d", i+1)
+		}
+	}
+	if _, ok := r.Update(200); !ok {
+		t.Fatalf("expected ok=true on the 15th update once the seed window has filled")
+	}
+}
@@ -1,142 +1,214 @@
 package main
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
-	"io"
+	"flag"
 	"log"
 	"math"
-	"net/http"
 	"os"
-	"strconv"
-	"sync"
 	"time"
 
 	"github.com/joho/godotenv"
-)
-
-var (
-	apiKey    string
-	apiSecret string
-	baseURL   = "https://api.binance.com"
-	client    = &http.Client{Timeout: 10 * time.Second}
 
-	cache24hVolume     = make(map[string]float64)
-	cache24hVolumeTime = make(map[string]int64)
-	cacheKlines        = make(map[string][]float64)
-	cacheKlinesTime    = make(map[string]int64)
-	mu                 sync.Mutex
+	"go_auto_trade/exchange"
+	"go_auto_trade/persistence"
 )
 
-type OrderResp struct {
-	Code  int    `json:"code"`
-	Msg   string `json:"msg"`
-	Fills []struct {
-		Price string `json:"price"`
-		Qty   string `json:"qty"`
-	} `json:"fills"`
-}
-
-type BalanceResp struct {
-	Balances []struct {
-		Asset  string `json:"asset"`
-		Free   string `json:"free"`
-		Locked string `json:"locked"`
-	} `json:"balances"`
-}
+// Kline is one candle's OHLC data, used by strategies that need more than the
+// closing price (e.g. ATR, which needs highs and lows).
+type Kline = exchange.Kline
 
 type PositionData struct {
 	InPosition   bool
 	LastBuyPrice float64
 	Qty          float64
-}
-
-type pingError struct {
-	statusCode int
-	body       string
-}
-
-func (e *pingError) Error() string {
-	return "ping failed, status code: " + strconv.Itoa(e.statusCode) + ", body: " + e.body
+	StopPrice    float64 // optional strategy-supplied trailing/hard stop; 0 = unset
 }
 
 func init() {
-	err := godotenv.Load()
-	if err != nil {
+	if err := godotenv.Load(); err != nil {
 		log.Fatalf("Error loading .env file: %v", err)
 	}
+}
 
-	apiKey = os.Getenv("BINANCE_API_KEY")
-	if apiKey == "" {
-		log.Fatalln("BINANCE_API_KEY is missing or empty in .env file")
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		runHistoryCommand(os.Args[2:])
+		return
 	}
 
-	apiSecret = os.Getenv("BINANCE_API_SECRET")
-	if apiSecret == "" {
-		log.Fatalln("BINANCE_API_SECRET is missing or empty in .env file")
+	backtestConfigPath := flag.String("backtest", "", "path to a backtest config JSON file; when set the bot replays history instead of trading live")
+	sessionsConfigPath := flag.String("sessions", "", "path to a sessions config JSON mapping symbols to exchange sessions; defaults to a single Binance session")
+	triarbConfigPath := flag.String("triarb", "", "path to a triangular-arbitrage config JSON; when set, each configured triple scans and trades alongside the autotrader loop")
+	persistenceConfigPath := flag.String("persistence", "", "path to a persistence config JSON file; defaults to a local JSON store under data/state")
+	flag.Parse()
+
+	if *backtestConfigPath != "" {
+		runBacktestMode(*backtestConfigPath, map[string]float64{
+			"BTCUSDT": 80,
+			"ETHUSDT": 40,
+		})
+		return
 	}
-}
 
-func main() {
-	err := testBinancePing()
+	sessionsCfg := defaultSessionsConfig()
+	if *sessionsConfigPath != "" {
+		cfg, err := loadSessionsConfig(*sessionsConfigPath)
+		if err != nil {
+			log.Fatalf("failed to load sessions config: %v", err)
+		}
+		sessionsCfg = cfg
+	}
+	portfolio := portfolioFromSessionsConfig(sessionsCfg)
+	sessions, symbolSession, symbols, err := buildSessions(sessionsCfg)
 	if err != nil {
-		log.Fatalf("Binance ping test failed: %v", err)
+		log.Fatalf("failed to build exchange sessions: %v", err)
 	}
-	log.Println("Binance ping test successful. Starting the bot...")
-	portfolio := map[string]float64{
-		"BTCUSDT": 80,
-		"ETHUSDT": 40,
+
+	persistenceCfg := defaultPersistenceConfig()
+	if *persistenceConfigPath != "" {
+		cfg, err := loadPersistenceConfig(*persistenceConfigPath)
+		if err != nil {
+			log.Fatalf("failed to load persistence config: %v", err)
+		}
+		persistenceCfg = cfg
 	}
+	posStore, err := buildStore(persistenceCfg)
+	if err != nil {
+		log.Fatalf("failed to open persistence store: %v", err)
+	}
+
+	processLifetime := make(chan struct{}) // closed never; background loops run until the process exits
+
+	const candleInterval = "15m"
+	store := NewMarketDataStore()
+	startMarketDataStreams(sessions, symbolSession, candleInterval, store, processLifetime)
+	liveExchange := NewLiveExchange(60, sessions, symbolSession, symbols, store)
+
+	if *triarbConfigPath != "" {
+		triArbCfg, err := loadTriArbConfig(*triarbConfigPath)
+		if err != nil {
+			log.Fatalf("failed to load triarb config: %v", err)
+		}
+		runTriArbPaths(sessions, triArbCfg, processLifetime)
+	}
+
+	if err := pingAllSessions(sessions); err != nil {
+		log.Fatalf("exchange ping test failed: %v", err)
+	}
+	log.Println("Exchange ping test successful. Starting the bot...")
 	autoTradePortfolio(
+		liveExchange,
 		portfolio,
+		buildStrategies(sessionsCfg, portfolio),
+		store.CandleClosed(),
+		posStore,
 		3600,
 		0.05,
 		0.1,
-		14,
-		30,
-		70,
-		12,
-		26,
-		9,
-		"15m",
+		candleInterval,
 		1e7,
 		60,
+		time.Minute,
 	)
 }
 
-func testBinancePing() error {
-	u := baseURL + "/api/v3/ping"
-	resp, err := client.Get(u)
-	if err != nil {
-		return err
+// startMarketDataStreams launches one WebSocket subscription per session
+// that supports exchange.StreamProvider, feeding store with every symbol
+// routed to that session. Symbols routed to sessions without streaming
+// support (e.g. Bitget today) keep being served by LiveExchange's REST-cached
+// fallback path instead.
+func startMarketDataStreams(sessions map[string]exchange.Exchange, symbolSession map[string]string, interval string, store *MarketDataStore, stop <-chan struct{}) {
+	symbolsBySession := make(map[string][]string)
+	for symbol, session := range symbolSession {
+		symbolsBySession[session] = append(symbolsBySession[session], symbol)
+	}
+	for name, sess := range sessions {
+		sp, ok := sess.(exchange.StreamProvider)
+		if !ok {
+			continue
+		}
+		symbols := symbolsBySession[name]
+		if len(symbols) == 0 {
+			continue
+		}
+		go store.Stream(sp, symbols, interval, stop)
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return &pingError{
-			statusCode: resp.StatusCode,
-			body:       string(body),
+}
+
+func pingAllSessions(sessions map[string]exchange.Exchange) error {
+	for name, sess := range sessions {
+		if err := sess.Ping(); err != nil {
+			return &sessionPingError{session: name, err: err}
 		}
 	}
 	return nil
 }
 
+type sessionPingError struct {
+	session string
+	err     error
+}
+
+func (e *sessionPingError) Error() string {
+	return "session " + e.session + ": " + e.err.Error()
+}
+
+func (e *sessionPingError) Unwrap() error {
+	return e.err
+}
+
+// defaultStrategies assigns the original RSI+MACD strategy to every symbol in
+// the portfolio, used by the backtest runner, which replays a fixed
+// portfolio and has no per-symbol StrategySpec to read. Live trading builds
+// its strategies from the sessions config via buildStrategies instead.
+func defaultStrategies(portfolio map[string]float64) map[string]Strategy {
+	strategies := make(map[string]Strategy, len(portfolio))
+	for sym := range portfolio {
+		strategies[sym] = newStrategy(StrategySpec{})
+	}
+	return strategies
+}
+
+func runBacktestMode(configPath string, portfolio map[string]float64) {
+	cfg, err := loadBacktestConfig(configPath)
+	if err != nil {
+		log.Fatalf("failed to load backtest config: %v", err)
+	}
+	report, err := RunBacktest(cfg, portfolio, defaultStrategies(portfolio), 3600, 0.05, 0.1, "15m")
+	if err != nil {
+		log.Fatalf("backtest failed: %v", err)
+	}
+	log.Printf("backtest done: trades=%d win_rate=%.2f%% max_drawdown=%.2f%% sharpe=%.2f",
+		report.TradeCount, report.WinRate*100, report.MaxDrawdown*100, report.Sharpe)
+	for sym, pnl := range report.RealizedPnL {
+		log.Printf("  %s realized_pnl=%.2f unrealized_pnl=%.2f", sym, pnl, report.UnrealizedPnL[sym])
+	}
+	if cfg.EquityCurveCSV != "" {
+		if err := report.WriteEquityCSV(cfg.EquityCurveCSV); err != nil {
+			log.Fatalf("failed to write equity curve: %v", err)
+		}
+		log.Printf("equity curve written to %s", cfg.EquityCurveCSV)
+	}
+}
+
+// autoTradePortfolio reacts to candleCloses (fed by a MarketDataStore
+// streaming from the exchange) instead of polling on a fixed timer. A
+// fallbackPollInterval ticker still runs alongside it, covering symbols
+// routed to a non-streaming session and the case where the stream drops
+// without candleCloses noticing.
 func autoTradePortfolio(
+	ex Exchange,
 	portfolio map[string]float64,
+	strategies map[string]Strategy,
+	candleCloses <-chan string,
+	store persistence.Store,
 	buyInterval int64,
 	stopLossPct float64,
 	takeProfitPct float64,
-	rsiPeriod int,
-	buyRSIThreshold float64,
-	sellRSIThreshold float64,
-	macdShort int,
-	macdLong int,
-	macdSignal int,
 	candleInterval string,
 	volumeThreshold float64,
 	refreshInterval int64,
+	fallbackPollInterval time.Duration,
 ) {
 	positions := make(map[string]*PositionData)
 	lastBuyTime := make(map[string]int64)
@@ -144,406 +216,150 @@ func autoTradePortfolio(
 		positions[sym] = &PositionData{InPosition: false, LastBuyPrice: 0, Qty: 0}
 		lastBuyTime[sym] = 0
 	}
+	rehydratePositions(store, positions, lastBuyTime)
+	fallback := time.NewTicker(fallbackPollInterval)
+	defer fallback.Stop()
 	for {
-		for sym, allocation := range portfolio {
-			posData := positions[sym]
-			inPosition := posData.InPosition
-			lastBuyPrice := posData.LastBuyPrice
-			tNow := time.Now().Unix()
-			if !inPosition {
-				if tNow-lastBuyTime[sym] >= buyInterval {
-					vol24h := get24hVolumeUSDT(sym, refreshInterval)
-					if vol24h >= volumeThreshold {
-						balUSDT := getBalance("USDT")
-						if balUSDT >= allocation {
-							klines := getKlines(sym, candleInterval, 50, refreshInterval)
-							if len(klines) >= macdLong {
-								rsiVal := calcRSI(klines, rsiPeriod)
-								_, _, mHist := calcMACD(klines, macdShort, macdLong, macdSignal)
-								if rsiVal <= buyRSIThreshold && mHist > 0 {
-									currentPrice := getCurrentPrice(sym)
-									if currentPrice > 0 {
-										qty := math.Floor((allocation/currentPrice)*100000) / 100000
-										_, avgFill, filledQty := placeMarketOrder(sym, "BUY", qty)
-										if filledQty > 0 {
-											positions[sym].InPosition = true
-											positions[sym].LastBuyPrice = avgFill
-											positions[sym].Qty = filledQty
-											lastBuyTime[sym] = tNow
-											log.Printf("Bought %s at %.4f, qty=%.5f", sym, avgFill, filledQty)
-										}
-									}
+		select {
+		case sym := <-candleCloses:
+			log.Printf("candle closed for %s, evaluating portfolio", sym)
+		case <-fallback.C:
+		}
+		tradePortfolioOnce(ex, portfolio, strategies, positions, lastBuyTime, store, time.Now().Unix(),
+			buyInterval, stopLossPct, takeProfitPct, candleInterval, volumeThreshold, refreshInterval)
+	}
+}
+
+// tradePortfolioOnce runs a single decision cycle for every symbol in
+// portfolio against the supplied Exchange at time tNow, delegating the
+// buy/sell call to each symbol's Strategy. It is shared by the live polling
+// loop in autoTradePortfolio and the historical replay loop in RunBacktest so
+// both exercise the exact same trading logic.
+func tradePortfolioOnce(
+	ex Exchange,
+	portfolio map[string]float64,
+	strategies map[string]Strategy,
+	positions map[string]*PositionData,
+	lastBuyTime map[string]int64,
+	store persistence.Store,
+	tNow int64,
+	buyInterval int64,
+	stopLossPct float64,
+	takeProfitPct float64,
+	candleInterval string,
+	volumeThreshold float64,
+	refreshInterval int64,
+) {
+	for sym, allocation := range portfolio {
+		posData := positions[sym]
+		strategy := strategies[sym]
+		inPosition := posData.InPosition
+		lastBuyPrice := posData.LastBuyPrice
+		if !inPosition {
+			if tNow-lastBuyTime[sym] >= buyInterval {
+				vol24h := ex.Get24hVolume(sym)
+				if vol24h >= volumeThreshold {
+					balUSDT := ex.GetBalance(sym, "USDT")
+					if balUSDT >= allocation {
+						klines := ex.GetKlines(sym, candleInterval, 50)
+						signal := strategy.OnKline(sym, klines)
+						if signal.Action == Buy {
+							currentPrice := ex.GetCurrentPrice(sym)
+							if currentPrice > 0 {
+								qty := allocation / currentPrice
+								avgFill, filledQty := ex.PlaceMarketOrder(sym, "BUY", qty)
+								if filledQty > 0 {
+									positions[sym].InPosition = true
+									positions[sym].LastBuyPrice = avgFill
+									positions[sym].Qty = filledQty
+									positions[sym].StopPrice = signal.StopPrice
+									lastBuyTime[sym] = tNow
+									log.Printf("Bought %s at %.4f, qty=%.5f", sym, avgFill, filledQty)
+									saveTrade(store, persistence.Trade{Symbol: sym, Side: "BUY", Price: avgFill, Qty: filledQty, TimeMs: tNow * 1000})
+									savePosition(store, sym, positions[sym], lastBuyTime[sym])
 								}
 							}
 						}
 					}
 				}
-			} else {
-				currentPrice := getCurrentPrice(sym)
-				if currentPrice > 0 && lastBuyPrice > 0 {
-					if currentPrice <= lastBuyPrice*(1-stopLossPct) {
-						coinSym := sym[:len(sym)-4]
-						balCoin := getBalance(coinSym)
-						if balCoin > 0 {
-							_, avgFill, filledQty := placeMarketOrder(sym, "SELL", roundDown(balCoin, 5))
-							if filledQty > 0 {
-								positions[sym].InPosition = false
-								positions[sym].LastBuyPrice = 0
-								positions[sym].Qty = 0
-								log.Printf("Stop-loss triggered for %s, sold at %.4f", sym, avgFill)
-							}
-						}
-					} else if currentPrice >= lastBuyPrice*(1+takeProfitPct) {
-						coinSym := sym[:len(sym)-4]
-						balCoin := getBalance(coinSym)
-						if balCoin > 0 {
-							_, avgFill, filledQty := placeMarketOrder(sym, "SELL", roundDown(balCoin, 5))
-							if filledQty > 0 {
-								positions[sym].InPosition = false
-								positions[sym].LastBuyPrice = 0
-								positions[sym].Qty = 0
-								log.Printf("Take-profit triggered for %s, sold at %.4f", sym, avgFill)
-							}
-						}
-					} else {
-						klines := getKlines(sym, candleInterval, 50, refreshInterval)
-						if len(klines) >= macdLong {
-							rsiVal := calcRSI(klines, rsiPeriod)
-							_, _, mHist := calcMACD(klines, macdShort, macdLong, macdSignal)
-							if rsiVal >= sellRSIThreshold && mHist < 0 {
-								coinSym := sym[:len(sym)-4]
-								balCoin := getBalance(coinSym)
-								if balCoin > 0 {
-									_, avgFill, filledQty := placeMarketOrder(sym, "SELL", roundDown(balCoin, 5))
-									if filledQty > 0 {
-										positions[sym].InPosition = false
-										positions[sym].LastBuyPrice = 0
-										positions[sym].Qty = 0
-										log.Printf("RSI+MACD sell signal triggered for %s, sold at %.4f", sym, avgFill)
-									}
-								}
-							}
-						}
+			}
+		} else {
+			currentPrice := ex.GetCurrentPrice(sym)
+			if currentPrice > 0 && lastBuyPrice > 0 {
+				if currentPrice <= lastBuyPrice*(1-stopLossPct) {
+					sellPosition(ex, sym, strategy, positions, lastBuyTime, store, "Stop-loss triggered for %s, sold at %.4f")
+				} else if currentPrice >= lastBuyPrice*(1+takeProfitPct) {
+					sellPosition(ex, sym, strategy, positions, lastBuyTime, store, "Take-profit triggered for %s, sold at %.4f")
+				} else if posData.StopPrice > 0 && currentPrice <= posData.StopPrice {
+					sellPosition(ex, sym, strategy, positions, lastBuyTime, store, "Strategy trailing stop triggered for %s, sold at %.4f")
+				} else {
+					klines := ex.GetKlines(sym, candleInterval, 50)
+					signal := strategy.OnKline(sym, klines)
+					if signal.StopPrice > posData.StopPrice {
+						positions[sym].StopPrice = signal.StopPrice
+						savePosition(store, sym, positions[sym], lastBuyTime[sym])
+					}
+					if signal.Action == Sell {
+						sellPosition(ex, sym, strategy, positions, lastBuyTime, store, "Strategy sell signal triggered for %s, sold at %.4f")
 					}
 				}
 			}
 		}
-		time.Sleep(10 * time.Second)
-	}
-}
-
-func get24hVolumeUSDT(symbol string, refreshInterval int64) float64 {
-	mu.Lock()
-	defer mu.Unlock()
-	now := time.Now().Unix()
-	if v, ok := cache24hVolume[symbol]; ok {
-		lastT := cache24hVolumeTime[symbol]
-		if (now - lastT) < refreshInterval {
-			return v
-		}
-	}
-	u := baseURL + "/api/v3/ticker/24hr?symbol=" + symbol
-	d := safeGet(u)
-	if d == nil {
-		cache24hVolume[symbol] = 0
-		cache24hVolumeTime[symbol] = now
-		return 0
-	}
-	quoteVol, ok := d["quoteVolume"].(string)
-	if !ok {
-		cache24hVolume[symbol] = 0
-		cache24hVolumeTime[symbol] = now
-		return 0
-	}
-	vol, err := strconv.ParseFloat(quoteVol, 64)
-	if err != nil {
-		vol = 0
-	}
-	cache24hVolume[symbol] = vol
-	cache24hVolumeTime[symbol] = now
-	return vol
-}
-
-func getKlines(symbol, interval string, limit int, refreshInterval int64) []float64 {
-	mu.Lock()
-	defer mu.Unlock()
-	now := time.Now().Unix()
-	cacheKey := symbol + "_" + interval + "_" + strconv.Itoa(limit)
-	if kl, ok := cacheKlines[cacheKey]; ok {
-		lastT := cacheKlinesTime[cacheKey]
-		if (now - lastT) < refreshInterval {
-			return kl
-		}
-	}
-	u := baseURL + "/api/v3/klines?symbol=" + symbol + "&interval=" + interval + "&limit=" + strconv.Itoa(limit)
-	arr := safeGetArray(u)
-	if arr == nil {
-		cacheKlines[cacheKey] = []float64{}
-		cacheKlinesTime[cacheKey] = now
-		return []float64{}
-	}
-	var closes []float64
-	for _, v := range arr {
-		vv, ok := v.([]interface{})
-		if ok && len(vv) >= 5 {
-			cs, _ := vv[4].(string)
-			cf, err := strconv.ParseFloat(cs, 64)
-			if err == nil {
-				closes = append(closes, cf)
-			}
-		}
 	}
-	cacheKlines[cacheKey] = closes
-	cacheKlinesTime[cacheKey] = now
-	return closes
 }
 
-func safeGetArray(url string) []interface{} {
-	for i := 0; i < 3; i++ {
-		resp, err := client.Get(url)
-		if err == nil && resp.StatusCode == 200 {
-			b, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			var result []interface{}
-			e := json.Unmarshal(b, &result)
-			if e == nil {
-				return result
-			}
+// sellPosition liquidates the symbol's full coin balance and resets its
+// position state, logging the fill with the caller-supplied message and
+// persisting both the resulting Trade record and the cleared PositionData.
+// The order quantity is rounded to the venue's tick size inside
+// PlaceMarketOrder, not by a hard-coded decimal count here.
+func sellPosition(ex Exchange, sym string, strategy Strategy, positions map[string]*PositionData, lastBuyTime map[string]int64, store persistence.Store, logMsg string) {
+	posData := positions[sym]
+	balCoin := ex.GetBalance(sym, ex.BaseAsset(sym))
+	if balCoin <= 0 {
+		return
+	}
+	avgFill, filledQty := ex.PlaceMarketOrder(sym, "SELL", balCoin)
+	if filledQty > 0 {
+		pnl := (avgFill - posData.LastBuyPrice) * filledQty
+		positions[sym].InPosition = false
+		positions[sym].LastBuyPrice = 0
+		positions[sym].Qty = 0
+		positions[sym].StopPrice = 0
+		if closer, ok := strategy.(PositionCloser); ok {
+			closer.OnPositionClosed(sym)
 		}
-		time.Sleep(2 * time.Second)
+		log.Printf(logMsg, sym, avgFill)
+		saveTrade(store, persistence.Trade{Symbol: sym, Side: "SELL", Price: avgFill, Qty: filledQty, TimeMs: time.Now().UnixMilli(), PnL: pnl})
+		savePosition(store, sym, positions[sym], lastBuyTime[sym])
 	}
-	return nil
 }
 
-func getCurrentPrice(symbol string) float64 {
-	u := baseURL + "/api/v3/ticker/price?symbol=" + symbol
-	d := safeGet(u)
-	if d == nil {
-		return 0
-	}
-	ps, ok := d["price"].(string)
-	if !ok {
+// calcATR returns the current Average True Range over the given period using
+// Wilder's smoothing, or 0 if there isn't enough history.
+func calcATR(klines []Kline, period int) float64 {
+	if len(klines) < period+1 {
 		return 0
 	}
-	val, err := strconv.ParseFloat(ps, 64)
-	if err != nil {
-		return 0
-	}
-	return val
-}
-
-func getBalance(asset string) float64 {
-	t := time.Now().UnixMilli()
-	q := "timestamp=" + strconv.FormatInt(t, 10)
-	sign := createSignature(q, apiSecret)
-	u := baseURL + "/api/v3/account?" + q + "&signature=" + sign
-	h := map[string]string{"X-MBX-APIKEY": apiKey}
-	resp := safeGetWithHeaders(u, h)
-	if resp == nil {
-		return 0
-	}
-	var bResp BalanceResp
-	j, _ := json.Marshal(resp)
-	json.Unmarshal(j, &bResp)
-	for _, b := range bResp.Balances {
-		if b.Asset == asset {
-			f, _ := strconv.ParseFloat(b.Free, 64)
-			return f
-		}
-	}
-	return 0
-}
-
-func placeMarketOrder(symbol, side string, quantity float64) (OrderResp, float64, float64) {
-	ts := time.Now().UnixMilli()
-	qs := "symbol=" + symbol + "&side=" + side + "&type=MARKET&quantity=" + strconv.FormatFloat(quantity, 'f', -1, 64) +
-		"&timestamp=" + strconv.FormatInt(ts, 10)
-	s := createSignature(qs, apiSecret)
-	u := baseURL + "/api/v3/order?" + qs + "&signature=" + s
-	h := map[string]string{"X-MBX-APIKEY": apiKey}
-	log.Printf("Placing %s order on %s, qty=%.5f", side, symbol, quantity)
-	r := safePostWithHeaders(u, h)
-	if r == nil {
-		log.Printf("Order response is nil for %s %s", symbol, side)
-		return OrderResp{Code: -999}, 0, 0
-	}
-	var o OrderResp
-	j, _ := json.Marshal(r)
-	json.Unmarshal(j, &o)
-	if o.Code < 0 {
-		log.Printf("Binance error code: %d, msg: %s", o.Code, o.Msg)
-		return o, 0, 0
-	}
-	ap, tq := parseOrderFills(o)
-	log.Printf("Order result: avg_price=%.5f, filled_qty=%.5f", ap, tq)
-	return o, ap, tq
-}
-
-func parseOrderFills(o OrderResp) (float64, float64) {
-	var totalCost float64
-	var totalQty float64
-	for _, f := range o.Fills {
-		p, _ := strconv.ParseFloat(f.Price, 64)
-		q, _ := strconv.ParseFloat(f.Qty, 64)
-		totalCost += p * q
-		totalQty += q
-	}
-	if totalQty > 0 {
-		return totalCost / totalQty, totalQty
-	}
-	return 0, 0
-}
-
-func calcRSI(data []float64, period int) float64 {
-	if len(data) < period+1 {
-		return 50
-	}
-	var gains float64
-	var losses float64
-	for i := 0; i < period; i++ {
-		diff := data[len(data)-1-i] - data[len(data)-2-i]
-		if diff > 0 {
-			gains += diff
-		} else {
-			losses -= diff
-		}
-	}
-	ag := gains / float64(period)
-	al := losses / float64(period)
-	if al == 0 {
-		return 100
-	}
-	rs := ag / al
-	r := 100 - (100 / (1 + rs))
-	return r
-}
-
-func calcMACD(data []float64, shortW, longW, signalW int) (float64, float64, float64) {
-	s := ema(data, shortW)
-	l := ema(data, longW)
-	var mLine []float64
-	if len(s) < len(l) {
-		df := len(l) - len(s)
-		var z []float64
-		for i := 0; i < df; i++ {
-			z = append(z, 0)
+	trueRanges := make([]float64, len(klines)-1)
+	for i := 1; i < len(klines); i++ {
+		high, low, prevClose := klines[i].High, klines[i].Low, klines[i-1].Close
+		tr := high - low
+		if v := math.Abs(high - prevClose); v > tr {
+			tr = v
 		}
-		s = append(z, s...)
-	} else if len(l) < len(s) {
-		df := len(s) - len(l)
-		var z []float64
-		for i := 0; i < df; i++ {
-			z = append(z, 0)
+		if v := math.Abs(low - prevClose); v > tr {
+			tr = v
 		}
-		l = append(z, l...)
-	}
-	for i := 0; i < len(l); i++ {
-		mLine = append(mLine, s[i]-l[i])
-	}
-	sig := ema(mLine, signalW)
-	var hist []float64
-	if len(sig) < len(mLine) {
-		df2 := len(mLine) - len(sig)
-		var z2 []float64
-		for i := 0; i < df2; i++ {
-			z2 = append(z2, 0)
-		}
-		sig = append(z2, sig...)
-	}
-	for i := 0; i < len(sig); i++ {
-		hist = append(hist, mLine[i]-sig[i])
+		trueRanges[i-1] = tr
 	}
-	if len(mLine) == 0 || len(sig) == 0 || len(hist) == 0 {
-		return 0, 0, 0
-	}
-	return mLine[len(mLine)-1], sig[len(sig)-1], hist[len(hist)-1]
-}
-
-func ema(data []float64, window int) []float64 {
-	if len(data) < window || window <= 0 {
-		return []float64{}
-	}
-	k := 2.0 / (float64(window) + 1)
-	var res []float64
-	s := 0.0
-	for i := 0; i < window; i++ {
-		s += data[i]
-	}
-	f := s / float64(window)
-	res = append(res, f)
-	for i := window; i < len(data); i++ {
-		val := data[i]*k + res[len(res)-1]*(1-k)
-		res = append(res, val)
-	}
-	return res
-}
-
-func safeGet(url string) map[string]interface{} {
-	for i := 0; i < 3; i++ {
-		resp, err := client.Get(url)
-		if err == nil && resp.StatusCode == 200 {
-			b, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			var result map[string]interface{}
-			_ = json.Unmarshal(b, &result)
-			if result != nil {
-				return result
-			}
-		}
-		time.Sleep(2 * time.Second)
+	var atr float64
+	for i := 0; i < period; i++ {
+		atr += trueRanges[i]
 	}
-	return nil
-}
-
-func safeGetWithHeaders(url string, headers map[string]string) map[string]interface{} {
-	for i := 0; i < 3; i++ {
-		req, _ := http.NewRequest("GET", url, nil)
-		for k, v := range headers {
-			req.Header.Set(k, v)
-		}
-		resp, err := client.Do(req)
-		if err == nil && resp.StatusCode == 200 {
-			b, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			var r map[string]interface{}
-			_ = json.Unmarshal(b, &r)
-			if r != nil {
-				return r
-			}
-		}
-		time.Sleep(2 * time.Second)
+	atr /= float64(period)
+	for i := period; i < len(trueRanges); i++ {
+		atr = (atr*float64(period-1) + trueRanges[i]) / float64(period)
 	}
-	return nil
-}
-
-func safePostWithHeaders(url string, headers map[string]string) map[string]interface{} {
-	for i := 0; i < 3; i++ {
-		req, _ := http.NewRequest("POST", url, nil)
-		for k, v := range headers {
-			req.Header.Set(k, v)
-		}
-		resp, err := client.Do(req)
-		if err == nil && (resp.StatusCode == 200 || resp.StatusCode == 201) {
-			b, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			var r map[string]interface{}
-			_ = json.Unmarshal(b, &r)
-			if r != nil {
-				return r
-			}
-		}
-		time.Sleep(2 * time.Second)
-	}
-	return nil
-}
-
-func createSignature(query, secret string) string {
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(query))
-	return hex.EncodeToString(h.Sum(nil))
-}
-
-func roundDown(val float64, decimals int) float64 {
-	p := math.Pow10(decimals)
-	return math.Floor(val*p) / p
+	return atr
 }
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestCalcATRConstantRange(t *testing.T) {
+	// Every candle has the same high-low range and a close equal to the
+	// next candle's prevClose, so every true range equals the range and
+	// the ATR (simple-average seed, then Wilder smoothing) stays exactly 2.
+	klines := []Kline{
+		{High: 11, Low: 9, Close: 10},
+		{High: 12, Low: 10, Close: 11},
+		{High: 13, Low: 11, Close: 12},
+		{High: 14, Low: 12, Close: 13},
+	}
+	if got := calcATR(klines, 2); got != 2 {
+		t.Errorf("calcATR = %v, want 2 for a constant true range", got)
+	}
+}
+
+func TestCalcATRInsufficientHistory(t *testing.T) {
+	klines := []Kline{{High: 11, Low: 9, Close: 10}}
+	if got := calcATR(klines, 5); got != 0 {
+		t.Errorf("calcATR with fewer than period+1 klines = %v, want 0", got)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"log"
+	"sync"
+
+	"go_auto_trade/exchange"
+)
+
+// marketDataWindow is the number of recent candles MarketDataStore retains
+// per symbol. The strategies in this package only ever look back a few tens
+// of candles, so this comfortably covers their warmup needs without growing
+// unbounded.
+const marketDataWindow = 500
+
+// MarketDataStore keeps a rolling window of candles and the latest traded
+// price per symbol, fed by a venue's WebSocket stream instead of REST
+// polling. CandleClosed emits the symbol whenever a new closed candle is
+// ingested, so autoTradePortfolio can react immediately instead of polling
+// on a timer.
+type MarketDataStore struct {
+	mu      sync.RWMutex
+	klines  map[string][]Kline
+	forming map[string]int64 // symbol -> OpenTimeMs of klines' last entry while it's still an unclosed candle
+	prices  map[string]float64
+	closed  chan string
+}
+
+func NewMarketDataStore() *MarketDataStore {
+	return &MarketDataStore{
+		klines:  make(map[string][]Kline),
+		forming: make(map[string]int64),
+		prices:  make(map[string]float64),
+		closed:  make(chan string, 64),
+	}
+}
+
+// CandleClosed returns the channel a candle's symbol is sent on whenever a
+// closed candle is ingested.
+func (s *MarketDataStore) CandleClosed() <-chan string {
+	return s.closed
+}
+
+// Klines returns a copy of the currently retained window for symbol, oldest
+// first, or nil if nothing has been streamed for it yet. The still-forming
+// candle (if the most recent kline event for symbol hasn't closed yet) is
+// withheld: strategies only ever see closed candles, so a 1-minute fallback
+// poll mid-way through a 15-minute candle can't feed a non-final close into
+// incremental indicator state.
+func (s *MarketDataStore) Klines(symbol string) []Kline {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	win := s.klines[symbol]
+	if n := len(win); n > 0 {
+		if openMs, ok := s.forming[symbol]; ok && win[n-1].OpenTimeMs == openMs {
+			win = win[:n-1]
+		}
+	}
+	if len(win) == 0 {
+		return nil
+	}
+	return append([]Kline(nil), win...)
+}
+
+// Price returns the latest known traded price for symbol and whether one has
+// been streamed yet.
+func (s *MarketDataStore) Price(symbol string) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.prices[symbol]
+	return p, ok
+}
+
+// Stream subscribes to streamer's kline/ticker streams for symbols and feeds
+// this store until stop is closed. It blocks, so callers run it in its own
+// goroutine; reconnect/backoff is handled inside streamer.
+func (s *MarketDataStore) Stream(streamer exchange.StreamProvider, symbols []string, interval string, stop <-chan struct{}) {
+	klineEvents := make(chan exchange.KlineEvent, 256)
+	tickerEvents := make(chan exchange.TickerEvent, 256)
+	go streamer.StreamKlines(symbols, interval, klineEvents, tickerEvents, stop)
+	for {
+		select {
+		case ev := <-klineEvents:
+			s.ingestKline(ev)
+		case ev := <-tickerEvents:
+			s.ingestTicker(ev)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (s *MarketDataStore) ingestKline(ev exchange.KlineEvent) {
+	s.mu.Lock()
+	win := s.klines[ev.Symbol]
+	if n := len(win); n > 0 && win[n-1].OpenTimeMs == ev.Kline.OpenTimeMs {
+		win[n-1] = ev.Kline
+	} else {
+		win = append(win, ev.Kline)
+		if len(win) > marketDataWindow {
+			win = win[len(win)-marketDataWindow:]
+		}
+	}
+	s.klines[ev.Symbol] = win
+	s.prices[ev.Symbol] = ev.Kline.Close
+	if ev.Closed {
+		delete(s.forming, ev.Symbol)
+	} else {
+		s.forming[ev.Symbol] = ev.Kline.OpenTimeMs
+	}
+	s.mu.Unlock()
+
+	if !ev.Closed {
+		return
+	}
+	select {
+	case s.closed <- ev.Symbol:
+	default:
+		log.Printf("market data: candle-close channel full, dropping event for %s", ev.Symbol)
+	}
+}
+
+func (s *MarketDataStore) ingestTicker(ev exchange.TickerEvent) {
+	s.mu.Lock()
+	s.prices[ev.Symbol] = ev.Price
+	s.mu.Unlock()
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go_auto_trade/persistence"
+)
+
+// PersistenceConfig selects and configures the persistence.Store backend used
+// to survive a restart without losing open positions or trade history.
+type PersistenceConfig struct {
+	Backend string                   `json:"backend"` // "json" (default) or "redis"
+	JSONDir string                   `json:"json_dir,omitempty"`
+	Redis   *persistence.RedisConfig `json:"redis,omitempty"`
+}
+
+func defaultPersistenceConfig() PersistenceConfig {
+	return PersistenceConfig{Backend: "json", JSONDir: "data/state"}
+}
+
+func loadPersistenceConfig(path string) (PersistenceConfig, error) {
+	cfg := defaultPersistenceConfig()
+	f, err := os.Open(path)
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func buildStore(cfg PersistenceConfig) (persistence.Store, error) {
+	switch cfg.Backend {
+	case "", "json":
+		dir := cfg.JSONDir
+		if dir == "" {
+			dir = "data/state"
+		}
+		return persistence.NewJSONStore(dir)
+	case "redis":
+		if cfg.Redis == nil {
+			return nil, fmt.Errorf("persistence: redis backend requires a \"redis\" config block")
+		}
+		return persistence.NewRedisStore(*cfg.Redis), nil
+	default:
+		return nil, fmt.Errorf("persistence: unknown backend %q", cfg.Backend)
+	}
+}
+
+// savePosition writes sym's current PositionData to store, keyed so
+// rehydratePositions can find it again on the next startup. store may be nil
+// (e.g. during a backtest), in which case this is a no-op. lastBuyTime is in
+// Unix seconds, as kept in the autotrader's lastBuyTime map.
+func savePosition(store persistence.Store, sym string, pos *PositionData, lastBuyTime int64) {
+	if store == nil {
+		return
+	}
+	rec := persistence.Position{
+		Symbol:        sym,
+		InPosition:    pos.InPosition,
+		LastBuyPrice:  pos.LastBuyPrice,
+		Qty:           pos.Qty,
+		StopPrice:     pos.StopPrice,
+		LastBuyTimeMs: lastBuyTime * 1000,
+	}
+	if err := store.Save("position/"+sym, rec); err != nil {
+		log.Printf("persistence: failed to save position for %s: %v", sym, err)
+	}
+}
+
+// saveTrade records one fill under a key unique to that fill, so trade
+// history accumulates rather than overwriting. store may be nil.
+func saveTrade(store persistence.Store, t persistence.Trade) {
+	if store == nil {
+		return
+	}
+	key := fmt.Sprintf("trade/%s/%d", t.Symbol, t.TimeMs)
+	if err := store.Save(key, t); err != nil {
+		log.Printf("persistence: failed to save trade for %s: %v", t.Symbol, err)
+	}
+}
+
+// rehydratePositions loads every persisted position back into positions and
+// lastBuyTime, so a restart picks up exactly where it left off instead of
+// forgetting open positions and missing their stop-loss/take-profit exits.
+// store may be nil, in which case this is a no-op.
+func rehydratePositions(store persistence.Store, positions map[string]*PositionData, lastBuyTime map[string]int64) {
+	if store == nil {
+		return
+	}
+	keys, err := store.List("position/")
+	if err != nil {
+		log.Printf("persistence: failed to list positions: %v", err)
+		return
+	}
+	for _, key := range keys {
+		var rec persistence.Position
+		if err := store.Load(key, &rec); err != nil {
+			log.Printf("persistence: failed to load %s: %v", key, err)
+			continue
+		}
+		pos, ok := positions[rec.Symbol]
+		if !ok {
+			continue
+		}
+		pos.InPosition = rec.InPosition
+		pos.LastBuyPrice = rec.LastBuyPrice
+		pos.Qty = rec.Qty
+		pos.StopPrice = rec.StopPrice
+		lastBuyTime[rec.Symbol] = rec.LastBuyTimeMs / 1000
+		log.Printf("persistence: rehydrated %s (in_position=%v qty=%.8f)", rec.Symbol, rec.InPosition, rec.Qty)
+	}
+}
+
+// runHistoryCommand implements the "history" CLI subcommand: it dumps every
+// persisted trade fill and the realized PnL summed per symbol from SELL
+// fills. Usage: go_auto_trade history [-persistence <path>]
+func runHistoryCommand(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	persistenceConfigPath := fs.String("persistence", "", "path to a persistence config JSON file; defaults to a local JSON store under data/state")
+	fs.Parse(args)
+
+	cfg := defaultPersistenceConfig()
+	if *persistenceConfigPath != "" {
+		loaded, err := loadPersistenceConfig(*persistenceConfigPath)
+		if err != nil {
+			log.Fatalf("failed to load persistence config: %v", err)
+		}
+		cfg = loaded
+	}
+	store, err := buildStore(cfg)
+	if err != nil {
+		log.Fatalf("failed to open persistence store: %v", err)
+	}
+
+	keys, err := store.List("trade/")
+	if err != nil {
+		log.Fatalf("failed to list trades: %v", err)
+	}
+	realizedPnL := make(map[string]float64)
+	for _, key := range keys {
+		var t persistence.Trade
+		if err := store.Load(key, &t); err != nil {
+			log.Printf("failed to load %s: %v", key, err)
+			continue
+		}
+		fmt.Printf("%-12s %-4s price=%.8f qty=%.8f fee=%.8f pnl=%.8f time=%s\n",
+			t.Symbol, t.Side, t.Price, t.Qty, t.Fee, t.PnL, time.UnixMilli(t.TimeMs).Format(time.RFC3339))
+		if t.Side == "SELL" {
+			realizedPnL[t.Symbol] += t.PnL
+		}
+	}
+	fmt.Println("\nRealized PnL by symbol:")
+	for sym, pnl := range realizedPnL {
+		fmt.Printf("  %s: %.8f\n", sym, pnl)
+	}
+}
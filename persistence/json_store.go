@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// JSONStore persists each key as its own ".json" file inside Dir, so state
+// survives a restart without any external dependency.
+type JSONStore struct {
+	Dir string
+}
+
+// NewJSONStore creates dir (and any missing parents) and returns a Store
+// backed by it.
+func NewJSONStore(dir string) (*JSONStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &JSONStore{Dir: dir}, nil
+}
+
+// path maps a key to a file inside Dir; keys are escaped so they can safely
+// contain the "/" used by this package's namespacing convention.
+func (s *JSONStore) path(key string) string {
+	return filepath.Join(s.Dir, url.PathEscape(key)+".json")
+}
+
+func (s *JSONStore) Save(key string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(key), b, 0o644)
+}
+
+func (s *JSONStore) Load(key string, v interface{}) error {
+	b, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (s *JSONStore) List(prefix string) ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		key, err := url.PathUnescape(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,77 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig configures a Redis-backed Store.
+type RedisConfig struct {
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	DB        int    `json:"db"`
+	Password  string `json:"password,omitempty"`
+	KeyPrefix string `json:"key_prefix,omitempty"` // namespaces keys so multiple bots can share one Redis instance
+}
+
+// RedisStore persists state in Redis, matching the persistence pattern used
+// in bbgo configs: every key is namespaced under KeyPrefix and stored as a
+// JSON string value.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisStore(cfg RedisConfig) *RedisStore {
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		}),
+		prefix: cfg.KeyPrefix,
+	}
+}
+
+func (s *RedisStore) fullKey(key string) string {
+	return s.prefix + key
+}
+
+func (s *RedisStore) Save(key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(context.Background(), s.fullKey(key), b, 0).Err()
+}
+
+func (s *RedisStore) Load(key string, v interface{}) error {
+	b, err := s.client.Get(context.Background(), s.fullKey(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (s *RedisStore) List(prefix string) ([]string, error) {
+	ctx := context.Background()
+	iter := s.client.Scan(ctx, 0, s.fullKey(prefix)+"*", 0).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, strings.TrimPrefix(iter.Val(), s.prefix))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
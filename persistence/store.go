@@ -0,0 +1,49 @@
+// Package persistence abstracts saving and rehydrating the bot's trading
+// state — open positions and trade history — behind a small Store
+// interface, so a restart doesn't forget an open position and miss its
+// stop-loss/take-profit exit. New backends are added by implementing Store,
+// not by touching the autotrader loop.
+package persistence
+
+import "errors"
+
+// ErrNotFound is returned by Load when key has never been saved.
+var ErrNotFound = errors.New("persistence: key not found")
+
+// Store is a minimal key-value persistence abstraction. Keys are opaque
+// strings; callers build their own namespacing convention (the autotrader
+// uses "position/<symbol>" and "trade/<symbol>/<time_ms>").
+type Store interface {
+	// Save serializes v under key, overwriting any existing value.
+	Save(key string, v interface{}) error
+	// Load deserializes the value stored under key into v, or returns
+	// ErrNotFound if key doesn't exist.
+	Load(key string, v interface{}) error
+	// List returns every key currently stored under prefix.
+	List(prefix string) ([]string, error)
+}
+
+// Position is the persisted state of one symbol's open (or most recently
+// closed) position, enough to rehydrate the autotrader's in-memory
+// positions/lastBuyTime maps after a restart.
+type Position struct {
+	Symbol        string  `json:"symbol"`
+	InPosition    bool    `json:"in_position"`
+	LastBuyPrice  float64 `json:"last_buy_price"`
+	Qty           float64 `json:"qty"`
+	StopPrice     float64 `json:"stop_price"`
+	LastBuyTimeMs int64   `json:"last_buy_time_ms"`
+}
+
+// Trade is a persisted record of one fill, written on every buy and sell so
+// trade history survives a restart and realized PnL can be recomputed from
+// it later.
+type Trade struct {
+	Symbol string  `json:"symbol"`
+	Side   string  `json:"side"`
+	Price  float64 `json:"price"`
+	Qty    float64 `json:"qty"`
+	Fee    float64 `json:"fee"`
+	TimeMs int64   `json:"time_ms"`
+	PnL    float64 `json:"pnl"` // only set for SELL fills
+}
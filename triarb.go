@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"go_auto_trade/exchange"
+)
+
+// TriArbSymbolSpec identifies one leg of a triangular path with its own tick
+// sizes, mirroring SymbolSpec so a leg's rounding doesn't need a lookup into
+// the main portfolio's symbol table.
+type TriArbSymbolSpec struct {
+	Symbol         string  `json:"symbol"`
+	Base           string  `json:"base"`
+	Quote          string  `json:"quote"`
+	AmountTickSize float64 `json:"amount_tick_size"`
+	PriceTickSize  float64 `json:"price_tick_size"`
+}
+
+func (s TriArbSymbolSpec) toSymbol() exchange.Symbol {
+	return exchange.Symbol{Base: s.Base, Quote: s.Quote, AmountTickSize: s.AmountTickSize, PriceTickSize: s.PriceTickSize}
+}
+
+// TriArbConfig describes one triangular arbitrage path: three legs forming
+// the cycle A/Q -> B/A -> B/Q (e.g. BTCUSDT, ETHBTC, ETHUSDT), the session
+// all three legs trade on, the per-asset inventory ceilings that bound how
+// large a cycle can be, and the minimum implied cross-rate (after fees)
+// required to trigger a trade.
+type TriArbConfig struct {
+	Session        string              `json:"session"`
+	Triple         [3]TriArbSymbolSpec `json:"triple"` // [A/Q, B/A, B/Q]
+	Limits         map[string]float64  `json:"limits"` // per-asset max qty per cycle
+	MinSpreadRatio float64             `json:"min_spread_ratio"`
+	TakerFeePct    float64             `json:"taker_fee_pct,omitempty"` // per-leg taker fee; all three legs are taker market orders
+}
+
+// TriArbPortfolioConfig is the top-level -triarb config file: one or more
+// independently-scanned triangular paths.
+type TriArbPortfolioConfig struct {
+	Paths []TriArbConfig `json:"paths"`
+}
+
+func loadTriArbConfig(path string) (TriArbPortfolioConfig, error) {
+	var cfg TriArbPortfolioConfig
+	f, err := os.Open(path)
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// runTriArbPaths launches one goroutine per configured path, so each triple
+// scans and trades independently of the others and of the indicator-based
+// autotrader loop, until stop is closed.
+func runTriArbPaths(sessions map[string]exchange.Exchange, cfg TriArbPortfolioConfig, stop <-chan struct{}) {
+	for _, path := range cfg.Paths {
+		sess, ok := sessions[path.Session]
+		if !ok {
+			log.Printf("triarb path %v references unknown session %q, skipping", path.Triple, path.Session)
+			continue
+		}
+		go runTriArb(sess, path, stop)
+	}
+}
+
+func runTriArb(sess exchange.Exchange, cfg TriArbConfig, stop <-chan struct{}) {
+	if cfg.MinSpreadRatio <= 0 {
+		cfg.MinSpreadRatio = 1.001
+	}
+	if cfg.TakerFeePct <= 0 {
+		cfg.TakerFeePct = 0.001
+	}
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := scanAndTrade(sess, cfg); err != nil {
+				log.Printf("triarb %v: %v", cfg.Triple, err)
+			}
+		}
+	}
+}
+
+// scanAndTrade fetches order-book depth for all three legs, computes the
+// forward and reverse implied cross-rates, and executes whichever cycle (if
+// either) clears cfg.MinSpreadRatio net of the three taker legs' fees. Each
+// leg is an independent taker market order, so the raw cross-rate has to
+// beat MinSpreadRatio by (1+TakerFeePct)^3 before a cycle is worth trading.
+func scanAndTrade(sess exchange.Exchange, cfg TriArbConfig) error {
+	symAQ, symBA, symBQ := cfg.Triple[0].toSymbol(), cfg.Triple[1].toSymbol(), cfg.Triple[2].toSymbol()
+
+	obAQ, err := sess.GetOrderBook(symAQ, 5)
+	if err != nil {
+		return fmt.Errorf("order book for %s: %w", symAQ, err)
+	}
+	obBA, err := sess.GetOrderBook(symBA, 5)
+	if err != nil {
+		return fmt.Errorf("order book for %s: %w", symBA, err)
+	}
+	obBQ, err := sess.GetOrderBook(symBQ, 5)
+	if err != nil {
+		return fmt.Errorf("order book for %s: %w", symBQ, err)
+	}
+
+	bidAQ, ok1 := obAQ.BestBid()
+	askAQ, ok2 := obAQ.BestAsk()
+	bidBA, ok3 := obBA.BestBid()
+	askBA, ok4 := obBA.BestAsk()
+	bidBQ, ok5 := obBQ.BestBid()
+	askBQ, ok6 := obBQ.BestAsk()
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6) {
+		return nil // one leg has an empty book; skip this tick
+	}
+
+	forward := bidAQ * bidBA / askBQ
+	reverse := bidBQ / (askBA * askAQ)
+	minRatioAfterFees := cfg.MinSpreadRatio * math.Pow(1+cfg.TakerFeePct, 3)
+
+	switch {
+	case forward >= minRatioAfterFees:
+		// sell B for A, sell A for Q, buy B with Q
+		sizeB, sizeA, sizeQ := sizeForwardCycle(cfg, bidBA, bidAQ)
+		if sizeB <= 0 {
+			return nil
+		}
+		finalB := sizeQ / askBQ
+		orders := executeCycle(sess, []triArbLeg{
+			{symBA, "SELL", sizeB},
+			{symAQ, "SELL", sizeA},
+			{symBQ, "BUY", finalB},
+		})
+		log.Printf("triarb forward executed %v: spread=%.5f size=%.8f%s", cfg.Triple, forward, sizeB, cfg.Triple[1].Base)
+		rebalanceResidual(sess, symBQ, filledQty(orders[0]), filledQty(orders[2]))
+	case reverse >= minRatioAfterFees:
+		// buy A with Q, buy B with A, sell B for Q
+		sizeA, sizeB := sizeReverseCycle(cfg, askAQ, askBA)
+		if sizeA <= 0 {
+			return nil
+		}
+		orders := executeCycle(sess, []triArbLeg{
+			{symAQ, "BUY", sizeA},
+			{symBA, "BUY", sizeB},
+			{symBQ, "SELL", sizeB},
+		})
+		log.Printf("triarb reverse executed %v: spread=%.5f size=%.8f%s", cfg.Triple, reverse, sizeA, cfg.Triple[0].Base)
+		rebalanceResidual(sess, symBQ, filledQty(orders[2]), filledQty(orders[1]))
+	}
+	return nil
+}
+
+func limitFor(cfg TriArbConfig, asset string) float64 {
+	if l, ok := cfg.Limits[asset]; ok && l > 0 {
+		return l
+	}
+	return math.MaxFloat64
+}
+
+// sizeForwardCycle bounds the forward cycle (sell B->A, sell A->Q, buy B
+// with Q) by whichever of the B, A, or Q per-asset limits is tightest once
+// converted through the observed rates, so a single cycle can never drift
+// any one leg's inventory past its configured cap.
+func sizeForwardCycle(cfg TriArbConfig, bidBA, bidAQ float64) (sizeB, sizeA, sizeQ float64) {
+	sizeB = limitFor(cfg, cfg.Triple[1].Base)
+	sizeA = sizeB * bidBA
+	if lim := limitFor(cfg, cfg.Triple[0].Base); sizeA > lim {
+		sizeA = lim
+		sizeB = sizeA / bidBA
+	}
+	sizeQ = sizeA * bidAQ
+	if lim := limitFor(cfg, cfg.Triple[0].Quote); sizeQ > lim {
+		sizeQ = lim
+		sizeA = sizeQ / bidAQ
+		sizeB = sizeA / bidBA
+	}
+	return sizeB, sizeA, sizeQ
+}
+
+// sizeReverseCycle bounds the reverse cycle (buy A with Q, buy B with A,
+// sell B for Q) the same way, starting from the Q limit.
+func sizeReverseCycle(cfg TriArbConfig, askAQ, askBA float64) (sizeA, sizeB float64) {
+	sizeQ := limitFor(cfg, cfg.Triple[0].Quote)
+	sizeA = sizeQ / askAQ
+	if lim := limitFor(cfg, cfg.Triple[0].Base); sizeA > lim {
+		sizeA = lim
+	}
+	sizeB = sizeA / askBA
+	if lim := limitFor(cfg, cfg.Triple[1].Base); sizeB > lim {
+		sizeB = lim
+		sizeA = sizeB * askBA
+	}
+	return sizeA, sizeB
+}
+
+type triArbLeg struct {
+	symbol exchange.Symbol
+	side   string
+	qty    float64
+}
+
+// executeCycle submits all three legs concurrently so the cycle executes as
+// close to atomically as a set of independent market orders allows.
+func executeCycle(sess exchange.Exchange, legs []triArbLeg) []*exchange.Order {
+	orders := make([]*exchange.Order, len(legs))
+	var wg sync.WaitGroup
+	for i, leg := range legs {
+		wg.Add(1)
+		go func(i int, leg triArbLeg) {
+			defer wg.Done()
+			order, err := sess.PlaceMarketOrder(leg.symbol, leg.side, leg.qty)
+			if err != nil {
+				log.Printf("triarb leg %s %s qty=%.8f failed: %v", leg.side, leg.symbol, leg.qty, err)
+				return
+			}
+			orders[i] = order
+		}(i, leg)
+	}
+	wg.Wait()
+	return orders
+}
+
+func filledQty(o *exchange.Order) float64 {
+	if o == nil {
+		return 0
+	}
+	return o.FilledQty
+}
+
+// rebalanceResidual flattens any net B exposure left over from a partially
+// filled leg by trading the difference on the B/Q leg, the pair that
+// opened/closed the cycle, so a partial fill never leaves unbounded drift.
+func rebalanceResidual(sess exchange.Exchange, bqSymbol exchange.Symbol, expectedBDelta, actualBDelta float64) {
+	const epsilon = 1e-9
+	residual := actualBDelta - expectedBDelta
+	var side string
+	var qty float64
+	switch {
+	case residual > epsilon:
+		side, qty = "SELL", residual
+	case residual < -epsilon:
+		side, qty = "BUY", -residual
+	default:
+		return
+	}
+	if _, err := sess.PlaceMarketOrder(bqSymbol, side, qty); err != nil {
+		log.Printf("triarb rebalance %s %s qty=%.8f failed: %v", side, bqSymbol, qty, err)
+	}
+}
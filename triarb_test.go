@@ -0,0 +1,47 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func testTriArbConfig(limits map[string]float64) TriArbConfig {
+	return TriArbConfig{
+		Triple: [3]TriArbSymbolSpec{
+			{Symbol: "AQ", Base: "A", Quote: "Q"},
+			{Symbol: "BA", Base: "B", Quote: "A"},
+			{Symbol: "BQ", Base: "B", Quote: "Q"},
+		},
+		Limits: limits,
+	}
+}
+
+func TestSizeForwardCycleUncapped(t *testing.T) {
+	cfg := testTriArbConfig(map[string]float64{"B": 10, "A": 1000, "Q": 100000})
+	sizeB, sizeA, sizeQ := sizeForwardCycle(cfg, 0.05, 50000)
+	if sizeB != 10 || sizeA != 0.5 || sizeQ != 25000 {
+		t.Errorf("sizeForwardCycle = (%v, %v, %v), want (10, 0.5, 25000)", sizeB, sizeA, sizeQ)
+	}
+}
+
+func TestSizeForwardCycleCappedByQuoteLimit(t *testing.T) {
+	// The Q limit (1000) is the tightest constraint once B=10 converts
+	// through to quote, so every leg must shrink to respect it. sizeB is
+	// 0.02/0.05, which float64 rounds to 0.39999999999999997, so it is
+	// compared within a tight tolerance.
+	cfg := testTriArbConfig(map[string]float64{"B": 10, "A": 1000, "Q": 1000})
+	sizeB, sizeA, sizeQ := sizeForwardCycle(cfg, 0.05, 50000)
+	if sizeQ != 1000 || sizeA != 0.02 || math.Abs(sizeB-0.4) > 1e-12 {
+		t.Errorf("sizeForwardCycle = (%v, %v, %v), want (0.4, 0.02, 1000)", sizeB, sizeA, sizeQ)
+	}
+}
+
+func TestSizeReverseCycleCappedByBaseLimit(t *testing.T) {
+	// The B limit (5) is the tightest constraint, so sizeA must shrink back
+	// down to match it once sizeB is clamped.
+	cfg := testTriArbConfig(map[string]float64{"Q": 100000, "A": 1000, "B": 5})
+	sizeA, sizeB := sizeReverseCycle(cfg, 50000, 0.05)
+	if sizeA != 0.25 || sizeB != 5 {
+		t.Errorf("sizeReverseCycle = (%v, %v), want (0.25, 5)", sizeA, sizeB)
+	}
+}